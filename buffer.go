@@ -0,0 +1,427 @@
+package geojson
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// BufferCap is the style of the ends of buffered lines.
+type BufferCap byte
+
+// BufferCap types
+const (
+	CapRound BufferCap = iota
+	CapFlat
+	CapSquare
+)
+
+// BufferJoin is the style of the corners of buffered lines and polygons.
+type BufferJoin byte
+
+// BufferJoin types
+const (
+	JoinRound BufferJoin = iota
+	JoinMiter
+	JoinBevel
+)
+
+// BufferOptions are the options for Buffer.
+type BufferOptions struct {
+	// QuadrantSegments is the number of segments that are used for a quarter
+	// of a circle. The default is 8.
+	QuadrantSegments int
+	// Cap is the style of the ends of lines and points. The default is
+	// CapRound. Points have no area with CapFlat.
+	Cap BufferCap
+	// Join is the style of the corners. The default is JoinRound.
+	Join BufferJoin
+	// MiterLimit is the largest ratio of the miter length to the buffer
+	// distance, which otherwise becomes a bevel. The default is 5.
+	MiterLimit float64
+}
+
+// Buffer returns the area that is within the distance in meters of an object,
+// as a Polygon or a MultiPolygon. A negative distance shrinks the area of
+// polygons, and results in an empty Polygon for other objects.
+//
+// The buffer is calculated on a plane that is centered at the object, which
+// is accurate for objects that span less than a few hundred kilometers.
+func Buffer(obj Object, meters float64, opts *BufferOptions) Object {
+	var bopts BufferOptions
+	if opts != nil {
+		bopts = *opts
+	}
+	if bopts.QuadrantSegments <= 0 {
+		bopts.QuadrantSegments = 8
+	}
+	if bopts.MiterLimit <= 0 {
+		bopts.MiterLimit = 5
+	}
+	if obj.Empty() {
+		return NewPolygon(nil)
+	}
+	b := &bufferer{
+		proj:   newPlaneProjection(obj.Rect()),
+		dist:   math.Abs(meters),
+		opts:   &bopts,
+		shrink: meters < 0,
+	}
+	polys := b.object(obj)
+	for _, poly := range polys {
+		b.unproject(poly)
+	}
+	return polysObject(polys)
+}
+
+// planeProjection projects points onto a plane in meters, which is centered
+// at a point.
+type planeProjection struct {
+	center         geometry.Point
+	scaleX, scaleY float64
+}
+
+func newPlaneProjection(rect geometry.Rect) planeProjection {
+	center := rect.Center()
+	perDegree := geo.DistanceTo(0, 0, 1, 0)
+	return planeProjection{
+		center: center,
+		scaleX: perDegree * math.Cos(center.Y*math.Pi/180),
+		scaleY: perDegree,
+	}
+}
+
+func (p planeProjection) project(point geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: (point.X - p.center.X) * p.scaleX,
+		Y: (point.Y - p.center.Y) * p.scaleY,
+	}
+}
+
+func (p planeProjection) unproject(point geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: point.X/p.scaleX + p.center.X,
+		Y: point.Y/p.scaleY + p.center.Y,
+	}
+}
+
+type bufferer struct {
+	proj   planeProjection
+	dist   float64
+	opts   *BufferOptions
+	shrink bool
+}
+
+// object returns the buffered polygons of an object, in projected meters.
+func (b *bufferer) object(obj Object) []*geometry.Poly {
+	var sets [][]*geometry.Poly
+	obj.ForEach(func(geom Object) bool {
+		var polys []*geometry.Poly
+		switch g := geom.(type) {
+		case *Point:
+			polys = b.point(g.base)
+		case *SimplePoint:
+			polys = b.point(g.Point)
+		case *LineString:
+			polys = b.line(b.projectSeries(&g.base), false)
+		case *Polygon:
+			polys = b.poly(&g.base)
+		case *Rect:
+			polys = b.poly(&geometry.Poly{Exterior: g.base})
+		case *Circle:
+			polys = b.object(g.getObject())
+		case *Feature:
+			polys = b.object(g.base)
+		default:
+			if !g.Empty() {
+				polys = b.poly(&geometry.Poly{Exterior: g.Rect()})
+			}
+		}
+		if len(polys) > 0 {
+			sets = append(sets, polys)
+		}
+		return true
+	})
+	return unionPolys(sets)
+}
+
+func (b *bufferer) projectSeries(series geometry.Series) []geometry.Point {
+	points := seriesPoints(series)
+	for i, point := range points {
+		points[i] = b.proj.project(point)
+		if b.dist != 0 {
+			// nothing is added to the points for a zero distance
+			points[i] = bufferSnap(points[i])
+		}
+	}
+	return points
+}
+
+func (b *bufferer) unproject(poly *geometry.Poly) {
+	unproject := func(ring geometry.Ring) geometry.Ring {
+		points := seriesPoints(ring)
+		for i, point := range points {
+			points[i] = b.proj.unproject(point)
+		}
+		return geometry.NewPoly(points, nil, nil).Exterior
+	}
+	poly.Exterior = unproject(poly.Exterior)
+	for i, hole := range poly.Holes {
+		poly.Holes[i] = unproject(hole)
+	}
+}
+
+func (b *bufferer) point(point geometry.Point) []*geometry.Poly {
+	if b.shrink || b.dist == 0 || math.IsNaN(point.X) || math.IsNaN(point.Y) {
+		return nil
+	}
+	center := bufferSnap(b.proj.project(point))
+	switch b.opts.Cap {
+	case CapFlat:
+		return nil
+	case CapSquare:
+		return []*geometry.Poly{bufferPoly(
+			geometry.Point{X: center.X - b.dist, Y: center.Y - b.dist},
+			geometry.Point{X: center.X + b.dist, Y: center.Y - b.dist},
+			geometry.Point{X: center.X + b.dist, Y: center.Y + b.dist},
+			geometry.Point{X: center.X - b.dist, Y: center.Y + b.dist},
+		)}
+	default:
+		return []*geometry.Poly{b.circle(center)}
+	}
+}
+
+func (b *bufferer) poly(poly *geometry.Poly) []*geometry.Poly {
+	if poly.Exterior == nil {
+		return nil
+	}
+	exterior := b.projectSeries(poly.Exterior)
+	var holes [][]geometry.Point
+	for _, hole := range poly.Holes {
+		holes = append(holes, b.projectSeries(hole))
+	}
+	base := []*geometry.Poly{geometry.NewPoly(exterior, holes, nil)}
+	if b.dist == 0 {
+		return base
+	}
+	// the buffer of the boundary is added to, or removed from, the polygon
+	sets := [][]*geometry.Poly{b.line(exterior, true)}
+	for _, hole := range holes {
+		sets = append(sets, b.line(hole, true))
+	}
+	boundary := unionPolys(sets)
+	if b.shrink {
+		return geometry.Overlay(base, boundary, geometry.OverlayDifference,
+			nil)
+	}
+	return geometry.Overlay(base, boundary, geometry.OverlayUnion, nil)
+}
+
+// line returns the buffer of a line, or of a ring when closed.
+func (b *bufferer) line(points []geometry.Point, closed bool) []*geometry.Poly {
+	if b.shrink && !closed || b.dist == 0 {
+		return nil
+	}
+	// remove the repeated points
+	var n int
+	for i := range points {
+		if i == 0 || points[i] != points[n-1] {
+			points[n] = points[i]
+			n++
+		}
+	}
+	points = points[:n]
+	if closed && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	if len(points) == 1 || (closed && len(points) < 3) {
+		return b.point(b.proj.unproject(points[0]))
+	}
+	var sets [][]*geometry.Poly
+	add := func(poly *geometry.Poly) {
+		if poly != nil {
+			sets = append(sets, []*geometry.Poly{poly})
+		}
+	}
+	nsegs := len(points) - 1
+	if closed {
+		nsegs = len(points)
+	}
+	seg := func(i int) (a, b geometry.Point) {
+		return points[i%len(points)], points[(i+1)%len(points)]
+	}
+	for i := 0; i < nsegs; i++ {
+		p1, p2 := seg(i)
+		add(b.segment(p1, p2))
+		if closed || i < nsegs-1 {
+			_, p3 := seg(i + 1)
+			add(b.join(p1, p2, p3))
+		}
+	}
+	if !closed {
+		add(b.cap(points[1], points[0]))
+		add(b.cap(points[len(points)-2], points[len(points)-1]))
+	}
+	return unionPolys(sets)
+}
+
+// normal returns the left unit normal of a segment, scaled to the buffer
+// distance.
+func (b *bufferer) normal(p1, p2 geometry.Point) (x, y float64) {
+	dx, dy := p2.X-p1.X, p2.Y-p1.Y
+	l := math.Hypot(dx, dy)
+	return -dy / l * b.dist, dx / l * b.dist
+}
+
+// segment returns the rectangle on both sides of a segment. The ends of the
+// segment are points of the rectangle, which makes the edges that are shared
+// with the joins and caps exactly the same.
+func (b *bufferer) segment(p1, p2 geometry.Point) *geometry.Poly {
+	nx, ny := b.normal(p1, p2)
+	return bufferPoly(
+		geometry.Point{X: p1.X - nx, Y: p1.Y - ny},
+		geometry.Point{X: p2.X - nx, Y: p2.Y - ny},
+		p2,
+		geometry.Point{X: p2.X + nx, Y: p2.Y + ny},
+		geometry.Point{X: p1.X + nx, Y: p1.Y + ny},
+		p1,
+	)
+}
+
+// join returns the corner on the outer side of the turn at p2.
+func (b *bufferer) join(p1, p2, p3 geometry.Point) *geometry.Poly {
+	n1x, n1y := b.normal(p1, p2)
+	n2x, n2y := b.normal(p2, p3)
+	cross := (p2.X-p1.X)*(p3.Y-p2.Y) - (p2.Y-p1.Y)*(p3.X-p2.X)
+	if cross == 0 {
+		return nil
+	}
+	if cross > 0 {
+		// turning left, which puts the outer side on the right
+		n1x, n1y, n2x, n2y = -n1x, -n1y, -n2x, -n2y
+	}
+	a := geometry.Point{X: p2.X + n1x, Y: p2.Y + n1y}
+	c := geometry.Point{X: p2.X + n2x, Y: p2.Y + n2y}
+	switch b.opts.Join {
+	case JoinBevel:
+		return bufferPoly(p2, a, c)
+	case JoinMiter:
+		mx, my := n1x+n2x, n1y+n2y
+		ml := math.Hypot(mx, my)
+		// the ratio of the miter length to the distance
+		ratio := 2 * b.dist / ml
+		if ml == 0 || ratio > b.opts.MiterLimit {
+			return bufferPoly(p2, a, c)
+		}
+		m := geometry.Point{
+			X: p2.X + mx/ml*b.dist*ratio, Y: p2.Y + my/ml*b.dist*ratio,
+		}
+		return bufferPoly(p2, a, m, c)
+	default:
+		start := math.Atan2(a.Y-p2.Y, a.X-p2.X)
+		end := math.Atan2(c.Y-p2.Y, c.X-p2.X)
+		return bufferPoly(b.arc(p2, a, c,
+			math.Remainder(end-start, 2*math.Pi))...)
+	}
+}
+
+// arc returns the center followed by the points of the arc from a to c,
+// which turns by delta radians. The arc ends at the exact points that are
+// provided, which are shared with the neighboring pieces.
+func (b *bufferer) arc(center, a, c geometry.Point, delta float64,
+) []geometry.Point {
+	start := math.Atan2(a.Y-center.Y, a.X-center.X)
+	step := math.Pi / 2 / float64(b.opts.QuadrantSegments)
+	n := int(math.Ceil(math.Abs(delta) / step))
+	points := []geometry.Point{center, a}
+	for i := 1; i < n; i++ {
+		th := start + delta*float64(i)/float64(n)
+		points = append(points, geometry.Point{
+			X: center.X + b.dist*math.Cos(th),
+			Y: center.Y + b.dist*math.Sin(th),
+		})
+	}
+	return append(points, c)
+}
+
+// cap returns the end of a line at p2, coming from p1.
+func (b *bufferer) cap(p1, p2 geometry.Point) *geometry.Poly {
+	switch b.opts.Cap {
+	case CapFlat:
+		return nil
+	}
+	nx, ny := b.normal(p1, p2)
+	left := geometry.Point{X: p2.X + nx, Y: p2.Y + ny}
+	right := geometry.Point{X: p2.X - nx, Y: p2.Y - ny}
+	if b.opts.Cap == CapSquare {
+		// the direction of the line is the normal turned clockwise
+		dx, dy := ny, -nx
+		return bufferPoly(left, p2, right,
+			geometry.Point{X: right.X + dx, Y: right.Y + dy},
+			geometry.Point{X: left.X + dx, Y: left.Y + dy},
+		)
+	}
+	// half of a circle, from the left side around the end to the right side
+	return bufferPoly(b.arc(p2, left, right, -math.Pi)...)
+}
+
+func (b *bufferer) circle(center geometry.Point) *geometry.Poly {
+	n := b.opts.QuadrantSegments * 4
+	points := make([]geometry.Point, n)
+	for i := range points {
+		th := 2 * math.Pi * float64(i) / float64(n)
+		points[i] = geometry.Point{
+			X: center.X + b.dist*math.Cos(th),
+			Y: center.Y + b.dist*math.Sin(th),
+		}
+	}
+	return bufferPoly(points...)
+}
+
+// bufferGrid is the size in meters of the grid that the points of the pieces
+// are snapped to. The pieces are calculated from different angles, and
+// snapping makes points that should be the same equal, which the overlay
+// needs.
+const bufferGrid = 1e-6
+
+func bufferSnap(point geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: math.Round(point.X/bufferGrid) * bufferGrid,
+		Y: math.Round(point.Y/bufferGrid) * bufferGrid,
+	}
+}
+
+// bufferPoly returns a polygon for the points of a ring, which is closed by
+// the function.
+func bufferPoly(points ...geometry.Point) *geometry.Poly {
+	ring := make([]geometry.Point, len(points), len(points)+1)
+	for i, point := range points {
+		ring[i] = bufferSnap(point)
+	}
+	return geometry.NewPoly(append(ring, ring[0]), nil, nil)
+}
+
+// unionPolys returns the union of the sets of polygons. The sets are merged
+// in pairs, which keeps the polygons being merged at similar sizes.
+func unionPolys(sets [][]*geometry.Poly) []*geometry.Poly {
+	if len(sets) == 0 {
+		return nil
+	}
+	for len(sets) > 1 {
+		var merged [][]*geometry.Poly
+		for i := 0; i < len(sets); i += 2 {
+			if i+1 == len(sets) {
+				merged = append(merged, sets[i])
+			} else {
+				merged = append(merged, geometry.Overlay(sets[i], sets[i+1],
+					geometry.OverlayUnion, nil))
+			}
+		}
+		sets = merged
+	}
+	return sets[0]
+}
@@ -0,0 +1,136 @@
+package geojson
+
+import (
+	"testing"
+
+	"github.com/tidwall/geojson/geo"
+)
+
+func TestBufferPoint(t *testing.T) {
+	center := P(-112.1, 33.4)
+	buf := Buffer(PO(center.X, center.Y), 1000, nil)
+	expect(t, buf.(*Polygon).Base().Exterior.NumPoints() == 33)
+	for _, bearing := range []float64{0, 45, 100, 200, 300} {
+		lat, lon := geo.DestinationPoint(center.Y, center.X, 990, bearing)
+		expect(t, buf.Contains(PO(lon, lat)))
+		lat, lon = geo.DestinationPoint(center.Y, center.X, 1010, bearing)
+		expect(t, !buf.Intersects(PO(lon, lat)))
+	}
+	square := Buffer(PO(center.X, center.Y), 1000,
+		&BufferOptions{Cap: CapSquare})
+	lat, lon := geo.DestinationPoint(center.Y, center.X, 1300, 45)
+	expect(t, square.Contains(PO(lon, lat)))
+	expect(t, !buf.Contains(PO(lon, lat)))
+	expect(t, Buffer(PO(center.X, center.Y), 1000,
+		&BufferOptions{Cap: CapFlat}).Empty())
+	expect(t, Buffer(PO(center.X, center.Y), -1000, nil).Empty())
+	expect(t, Buffer(PO(center.X, center.Y), 0, nil).Empty())
+	expect(t, Buffer(NewPolygon(nil), 1000, nil).Empty())
+	coarse := Buffer(PO(center.X, center.Y), 1000,
+		&BufferOptions{QuadrantSegments: 2})
+	expect(t, coarse.(*Polygon).Base().Exterior.NumPoints() == 9)
+}
+
+func TestBufferLineString(t *testing.T) {
+	route := expectJSON(t,
+		`{"type":"LineString","coordinates":[[-112,33],[-111.99,33],[-111.99,33.01]]}`,
+		nil)
+	near := func(lon, lat, meters, bearing float64) Object {
+		lat, lon = geo.DestinationPoint(lat, lon, meters, bearing)
+		return PO(lon, lat)
+	}
+	buf := Buffer(route, 500, nil)
+	_, ok := buf.(*Polygon)
+	expect(t, ok)
+	expect(t, buf.Contains(route))
+	expect(t, buf.Contains(near(-111.995, 33, 490, 0)))
+	expect(t, buf.Contains(near(-111.995, 33, 490, 180)))
+	expect(t, !buf.Contains(near(-111.995, 33, 510, 180)))
+	// round cap and join
+	expect(t, buf.Contains(near(-112, 33, 490, 270)))
+	expect(t, buf.Contains(near(-111.99, 33, 490, 135)))
+	expect(t, !buf.Contains(near(-111.99, 33, 600, 135)))
+	// flat caps end at the line
+	flat := Buffer(route, 500, &BufferOptions{Cap: CapFlat})
+	expect(t, !flat.Contains(near(-112, 33, 100, 270)))
+	expect(t, flat.Contains(near(-112, 33, 100, 90)))
+	square := Buffer(route, 500, &BufferOptions{Cap: CapSquare})
+	expect(t, square.Contains(near(-112, 33, 600, 225)))
+	expect(t, !buf.Contains(near(-112, 33, 600, 225)))
+	// the outer corner
+	miter := Buffer(route, 500, &BufferOptions{Join: JoinMiter})
+	bevel := Buffer(route, 500, &BufferOptions{Join: JoinBevel})
+	corner := near(-111.99, 33, 650, 135)
+	expect(t, miter.Contains(corner))
+	expect(t, !bevel.Contains(corner))
+	expect(t, !buf.Contains(corner))
+	expect(t, bevel.Contains(near(-111.99, 33, 300, 135)))
+	// a small miter limit makes a bevel
+	limited := Buffer(route, 500,
+		&BufferOptions{Join: JoinMiter, MiterLimit: 1.1})
+	expect(t, !limited.Contains(corner))
+	// the inside of the corner
+	expect(t, buf.Contains(near(-111.99, 33, 400, 315)))
+}
+
+func TestBufferPolygon(t *testing.T) {
+	poly := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[-112,33],[-111.98,33],[-111.98,33.02],[-112,33.02],[-112,33]],
+		[[-111.995,33.005],[-111.985,33.005],[-111.985,33.015],
+		[-111.995,33.015],[-111.995,33.005]]
+	]}`, nil)
+	grown := Buffer(poly, 200, nil)
+	expect(t, grown.Contains(poly))
+	// the hole shrinks but does not close
+	expect(t, grown.Contains(PO(-111.9945, 33.01)))
+	expect(t, !grown.Contains(PO(-111.99, 33.01)))
+	expect(t, len(grown.(*Polygon).Base().Holes) == 1)
+	expect(t, grown.Contains(PO(-112.001, 33.01)))
+	expect(t, !grown.Intersects(PO(-112.003, 33.01)))
+	// a larger buffer fills the hole
+	filled := Buffer(poly, 600, nil)
+	expect(t, len(filled.(*Polygon).Base().Holes) == 0)
+	expect(t, filled.Contains(PO(-111.99, 33.01)))
+	// shrinking
+	shrunk := Buffer(poly, -200, nil)
+	ext := shrunk.(*Polygon).Base().Exterior
+	for i := 0; i < ext.NumPoints(); i++ {
+		expect(t, poly.Contains(PO(ext.PointAt(i).X, ext.PointAt(i).Y)))
+	}
+	expect(t, !shrunk.Intersects(PO(-111.9995, 33.01)))
+	expect(t, shrunk.Contains(PO(-111.9975, 33.01)))
+	expect(t, Buffer(poly, -2000, nil).Empty())
+	expect(t, Buffer(poly, 0, nil).Contains(poly))
+	rect := Buffer(RO(-112, 33, -111.99, 33.01), 200, nil)
+	expect(t, rect.Contains(PO(-112.001, 33.005)))
+}
+
+func TestBufferMulti(t *testing.T) {
+	points := expectJSON(t,
+		`{"type":"MultiPoint","coordinates":[[-112,33],[-111.9,33]]}`, nil)
+	apart := Buffer(points, 1000, nil)
+	expect(t, len(apart.(*MultiPolygon).Children()) == 2)
+	together := Buffer(points, 5000, nil)
+	_, ok := together.(*Polygon)
+	expect(t, ok)
+	expect(t, together.Contains(PO(-111.95, 33)))
+	lines := expectJSON(t, `{"type":"MultiLineString","coordinates":[
+		[[-112,33],[-111.99,33]],[[-112,33.1],[-111.99,33.1]]
+	]}`, nil)
+	buf := Buffer(lines, 100, nil)
+	expect(t, len(buf.(*MultiPolygon).Children()) == 2)
+	for _, line := range lines.(*MultiLineString).Children() {
+		expect(t, buf.Contains(line))
+	}
+	feature := expectJSON(t, `{"type":"Feature","geometry":{"type":"Point",`+
+		`"coordinates":[-112,33]},"properties":{}}`, nil)
+	expect(t, Buffer(feature, 1000, nil).Contains(PO(-112.001, 33.001)))
+	coll := expectJSON(t, `{"type":"GeometryCollection","geometries":[
+		{"type":"Point","coordinates":[-112,33]},
+		{"type":"LineString","coordinates":[[-112,33],[-111.99,33]]}
+	]}`, nil)
+	buf = Buffer(coll, 100, nil)
+	for _, child := range coll.(*GeometryCollection).Children() {
+		expect(t, buf.Contains(child))
+	}
+}
@@ -0,0 +1,124 @@
+package geojson
+
+import "github.com/tidwall/geojson/geometry"
+
+// IntersectsCell returns true when an object is in a cell of a grid, such as
+// a geohash cell or a map tile, where the in function returns true for the
+// points that are in the cell. The areas of the object must overlap the
+// inside of the cell, and not only touch its edges. A point is in the cell
+// when the in function says so, and a line is in the cell when it has a
+// point in the cell, or intersects the inside of the cell, or an edge of the
+// cell whose points are in the cell. The points on an edge between two cells
+// are usually in only one of the cells, such as with GeohashEncode, which
+// puts a line along the edge in only one cell too.
+func IntersectsCell(obj Object, cell geometry.Rect,
+	in func(point geometry.Point) bool,
+) bool {
+	switch obj := obj.(type) {
+	case *Point, *SimplePoint:
+		return in(obj.Center())
+	case *LineString:
+		line := obj.Base()
+		for i := 0; i < line.NumPoints(); i++ {
+			if in(line.PointAt(i)) {
+				return true
+			}
+		}
+		edges := cellEdgesIn(cell, in)
+		var hit bool
+		line.Search(cell, func(seg geometry.Segment, _ int) bool {
+			hit = cellSegmentHits(seg, cell, edges)
+			return !hit
+		})
+		return hit
+	case *Polygon:
+		return cellPolyHits(obj.Base(), cell)
+	case *Rect:
+		return cellPolyHits(&geometry.Poly{Exterior: obj.Base()}, cell)
+	case *Circle:
+		return geoDistancePointRect(obj.Center(), cell) < obj.meters
+	case *Feature:
+		return IntersectsCell(obj.Base(), cell, in)
+	case Collection:
+		var hit bool
+		obj.Search(cell, func(child Object) bool {
+			hit = IntersectsCell(child, cell, in)
+			return !hit
+		})
+		return hit
+	}
+	return obj.Intersects(NewRect(cell))
+}
+
+// cellEdges are the edges of a cell that have their points in the cell, in
+// the order west, east, south, and north.
+type cellEdges [4]bool
+
+// cellEdgesIn returns the edges of a cell that have their points in the
+// cell, by testing the middle of each edge.
+func cellEdgesIn(cell geometry.Rect, in func(point geometry.Point) bool,
+) cellEdges {
+	center := cell.Center()
+	return cellEdges{
+		in(geometry.Point{X: cell.Min.X, Y: center.Y}),
+		in(geometry.Point{X: cell.Max.X, Y: center.Y}),
+		in(geometry.Point{X: center.X, Y: cell.Min.Y}),
+		in(geometry.Point{X: center.X, Y: cell.Max.Y}),
+	}
+}
+
+// cellPolyHits returns true when the inside of a polygon overlaps the inside
+// of a cell. Either an edge of the polygon crosses the inside of the cell, or
+// the inside of the cell is all in or all out of the polygon.
+func cellPolyHits(poly *geometry.Poly, cell geometry.Rect) bool {
+	if !poly.Rect().IntersectsRect(cell) {
+		return false
+	}
+	for _, ring := range polyRings(poly) {
+		var hit bool
+		ring.Search(cell, func(seg geometry.Segment, _ int) bool {
+			hit = cellSegmentHits(seg, cell, cellEdges{})
+			return !hit
+		})
+		if hit {
+			return true
+		}
+	}
+	return poly.ContainsPoint(cell.Center())
+}
+
+// cellSegmentHits returns true when a segment intersects the inside of a
+// cell, or one of the edges of the cell.
+func cellSegmentHits(seg geometry.Segment, cell geometry.Rect,
+	edges cellEdges,
+) bool {
+	// the range of the segment, from 0 to 1, that is in the cell, with ends
+	// that are open or closed
+	lo, hi := 0.0, 1.0
+	loOpen, hiOpen := false, false
+	clip := func(a, d, min, max float64, minEdge, maxEdge bool) bool {
+		if d == 0 {
+			return (a > min || a == min && minEdge) &&
+				(a < max || a == max && maxEdge)
+		}
+		t0, t1 := (min-a)/d, (max-a)/d
+		open0, open1 := !minEdge, !maxEdge
+		if d < 0 {
+			t0, t1, open0, open1 = t1, t0, open1, open0
+		}
+		if t0 > lo || t0 == lo && open0 {
+			lo, loOpen = t0, open0
+		}
+		if t1 < hi || t1 == hi && open1 {
+			hi, hiOpen = t1, open1
+		}
+		return true
+	}
+	if !clip(seg.A.X, seg.B.X-seg.A.X, cell.Min.X, cell.Max.X,
+		edges[0], edges[1]) ||
+		!clip(seg.A.Y, seg.B.Y-seg.A.Y, cell.Min.Y, cell.Max.Y,
+			edges[2], edges[3]) {
+		return false
+	}
+	return lo < hi || lo == hi && !loOpen && !hiOpen
+}
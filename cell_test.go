@@ -0,0 +1,54 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestIntersectsCell(t *testing.T) {
+	// a grid of unit cells, where the points on the west and south edges
+	// are in the cell
+	cell := R(0, 0, 1, 1)
+	in := func(point geometry.Point) bool {
+		return math.Floor(point.X) == 0 && math.Floor(point.Y) == 0
+	}
+	hits := func(obj Object) bool {
+		return IntersectsCell(obj, cell, in)
+	}
+	expect(t, hits(PO(0, 0)))
+	expect(t, hits(PO(0.5, 0.99999999)))
+	expect(t, !hits(PO(1, 0.5)))
+	expect(t, !hits(PO(0.5, 1)))
+	// lines along the edges
+	line := func(points ...geometry.Point) Object {
+		return NewLineString(geometry.NewLine(points, nil))
+	}
+	expect(t, hits(line(P(0, -1), P(0, 2))))
+	expect(t, hits(line(P(-1, 0), P(2, 0))))
+	expect(t, !hits(line(P(1, -1), P(1, 2))))
+	expect(t, !hits(line(P(-1, 1), P(2, 1))))
+	// lines that cross the inside, or only touch a corner
+	expect(t, hits(line(P(-1, 0.5), P(2, 0.6))))
+	expect(t, hits(line(P(-0.5, 0.5), P(0.5, -0.5))))
+	expect(t, hits(line(P(-1, -1), P(2, 2))))
+	expect(t, !hits(line(P(0.5, 1.5), P(1.5, 0.5))))
+	expect(t, !hits(line(P(2, 0), P(1, 1), P(2, 2))))
+	// areas that overlap the inside, or only touch the edges
+	expect(t, hits(RO(0.99999999, 0.5, 2, 2)))
+	expect(t, !hits(RO(1, 0, 2, 1)))
+	expect(t, !hits(RO(-1, -1, 0, 0)))
+	expect(t, hits(RO(-1, -1, 2, 2)))
+	expect(t, hits(RO(0, 0, 1, 1)))
+	holed := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[-1,-1],[2,-1],[2,2],[-1,2],[-1,-1]],
+		[[0,0],[1,0],[1,1],[0,1],[0,0]]
+	]}`, nil)
+	expect(t, !hits(holed))
+	// features and collections
+	expect(t, hits(expectJSON(t, `{"type":"Feature","geometry":
+		{"type":"Point","coordinates":[0.5,0.5]},"properties":{}}`, nil)))
+	expect(t, !hits(expectJSON(t, `{"type":"MultiPoint",
+		"coordinates":[[1,1],[1,0],[0,1]]}`, nil)))
+}
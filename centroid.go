@@ -0,0 +1,193 @@
+package geojson
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// centroidSum is the weighted sum of the centroids of the parts of an object
+// with the highest dimension, which is 0 for points, 1 for lines, and 2 for
+// areas.
+type centroidSum struct {
+	dim    int
+	x, y   float64
+	weight float64
+	count  int
+}
+
+func (sum *centroidSum) add(dim int, point geometry.Point, weight float64) {
+	if sum.count > 0 && dim < sum.dim {
+		return
+	}
+	if sum.count == 0 || dim > sum.dim {
+		*sum = centroidSum{dim: dim}
+	}
+	sum.x += point.X * weight
+	sum.y += point.Y * weight
+	sum.weight += weight
+	sum.count++
+}
+
+func (sum *centroidSum) addLine(line *geometry.Line) {
+	if length := line.Length(); length > 0 {
+		sum.add(1, line.Centroid(), length)
+		return
+	}
+	for i := 0; i < line.NumPoints(); i++ {
+		sum.add(0, line.PointAt(i), 1)
+	}
+}
+
+func (sum *centroidSum) addPoly(poly *geometry.Poly) {
+	if area := poly.Area(); area > 0 {
+		sum.add(2, poly.Centroid(), area)
+		return
+	}
+	line := geometry.NewLine(seriesPoints(poly.Exterior), nil)
+	sum.addLine(line)
+}
+
+func (sum *centroidSum) addObject(obj Object) {
+	if obj.Empty() {
+		return
+	}
+	switch obj := obj.(type) {
+	case *Point, *SimplePoint:
+		sum.add(0, obj.Center(), 1)
+	case *LineString:
+		sum.addLine(obj.Base())
+	case *Polygon:
+		sum.addPoly(obj.Base())
+	case *Rect:
+		sum.addPoly(obj.Polygon().(*Polygon).Base())
+	case *Circle:
+		// the center of the circle, weighted by the area of its polygon
+		if area := circlePlanarArea(obj); area > 0 {
+			sum.add(2, obj.Center(), area)
+			return
+		}
+		sum.add(0, obj.Center(), 1)
+	case *Feature:
+		sum.addObject(obj.Base())
+	case Collection:
+		for _, child := range obj.Children() {
+			sum.addObject(child)
+		}
+	}
+}
+
+// circlePlanarArea returns the planar area of the polygon of a circle, which
+// is a MultiPolygon with a piece on each side when the circle crosses the
+// antimeridian.
+func circlePlanarArea(circle *Circle) float64 {
+	var area float64
+	switch poly := circle.Polygon().(type) {
+	case *Polygon:
+		area = poly.Base().Area()
+	case *MultiPolygon:
+		for _, child := range poly.Children() {
+			if child, ok := child.(*Polygon); ok {
+				area += child.Base().Area()
+			}
+		}
+	}
+	return area
+}
+
+// Centroid returns the centroid of the object, which is the area-weighted
+// centroid of its polygons, or the length-weighted centroid of its lines
+// when it has no polygons, or the mean of its points when it has neither.
+// The parts of a collection that have a lower dimension than its other parts
+// do not change the centroid. The centroid of a concave polygon may be
+// outside of the polygon, see PointOnSurface.
+//
+// Unlike the Center method, which returns the center of the bounding
+// rectangle, the centroid is at the center of mass of the object.
+func Centroid(obj Object) geometry.Point {
+	var sum centroidSum
+	sum.addObject(obj)
+	if sum.weight == 0 {
+		return obj.Center()
+	}
+	return geometry.Point{X: sum.x / sum.weight, Y: sum.y / sum.weight}
+}
+
+// PointOnSurface returns a point that is on the object. For an object with
+// polygons, the point is inside of its largest polygon, which makes it a
+// good place for a label. Otherwise, the point is the vertex of the lines,
+// or the point, that is nearest to the centroid.
+func PointOnSurface(obj Object) geometry.Point {
+	centroid := Centroid(obj)
+	var best geometry.Point
+	var bestDim, bestCount int
+	var bestArea, bestDist float64
+	var visit func(obj Object)
+	try := func(dim int, point geometry.Point, area float64) {
+		dist := math.Hypot(point.X-centroid.X, point.Y-centroid.Y)
+		if bestCount > 0 {
+			if dim < bestDim {
+				return
+			}
+			if dim == bestDim {
+				if dim == 2 && area <= bestArea {
+					return
+				}
+				if dim < 2 && dist >= bestDist {
+					return
+				}
+			}
+		}
+		best, bestDim, bestArea, bestDist = point, dim, area, dist
+		bestCount++
+	}
+	tryLine := func(line *geometry.Line) {
+		dim := 0
+		if line.Length() > 0 {
+			dim = 1
+		}
+		for i := 0; i < line.NumPoints(); i++ {
+			try(dim, line.PointAt(i), 0)
+		}
+	}
+	tryPoly := func(poly *geometry.Poly) {
+		if area := poly.Area(); area > 0 {
+			try(2, poly.PointOnSurface(), area)
+			return
+		}
+		tryLine(geometry.NewLine(seriesPoints(poly.Exterior), nil))
+	}
+	visit = func(obj Object) {
+		if obj.Empty() {
+			return
+		}
+		switch obj := obj.(type) {
+		case *Point, *SimplePoint:
+			try(0, obj.Center(), 0)
+		case *LineString:
+			tryLine(obj.Base())
+		case *Polygon:
+			tryPoly(obj.Base())
+		case *Rect:
+			tryPoly(obj.Polygon().(*Polygon).Base())
+		case *Circle:
+			// the center of the circle is inside of its polygon
+			if area := circlePlanarArea(obj); area > 0 {
+				try(2, obj.Center(), area)
+				return
+			}
+			try(0, obj.Center(), 0)
+		case *Feature:
+			visit(obj.Base())
+		case Collection:
+			for _, child := range obj.Children() {
+				visit(child)
+			}
+		}
+	}
+	visit(obj)
+	if bestCount == 0 {
+		return obj.Center()
+	}
+	return best
+}
@@ -0,0 +1,63 @@
+package geojson
+
+import "testing"
+
+func TestCentroid(t *testing.T) {
+	lshape := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,2],[2,2],[2,10],[0,10],[0,0]]
+	]}`, nil)
+	c := Centroid(lshape)
+	expect(t, c == lshape.(*Polygon).Base().Centroid())
+	expect(t, c != lshape.Center())
+	expect(t, lshape.Contains(NewPoint(PointOnSurface(lshape))))
+	expect(t, !lshape.Contains(NewPoint(lshape.Center())))
+
+	// the lines and points do not move the centroid of the polygons
+	obj := expectJSON(t, `{"type":"GeometryCollection","geometries":[
+		{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,2],[0,2],[0,0]]]},
+		{"type":"Polygon","coordinates":[[[4,0],[6,0],[6,2],[4,2],[4,0]]]},
+		{"type":"LineString","coordinates":[[100,100],[200,100]]},
+		{"type":"Point","coordinates":[-100,-50]}
+	]}`, nil)
+	expect(t, Centroid(obj) == P(3, 1))
+	p := PointOnSurface(obj)
+	expect(t, p.X >= 0 && p.X <= 2 && p.Y > 0 && p.Y < 2)
+
+	lines := expectJSON(t, `{"type":"MultiLineString","coordinates":[
+		[[0,0],[4,0]],[[0,2],[2,2]]
+	]}`, nil)
+	c = Centroid(lines)
+	expect(t, c.X == (2*4+1*2)/6.0 && c.Y == (0*4+2*2)/6.0)
+	expect(t, PointOnSurface(lines) == P(2, 2))
+
+	points := expectJSON(t, `{"type":"Feature","geometry":{
+		"type":"MultiPoint","coordinates":[[0,0],[1,0],[5,0]]
+	},"properties":{}}`, nil)
+	expect(t, Centroid(points) == P(2, 0))
+	expect(t, PointOnSurface(points) == P(1, 0))
+
+	circle := NewCircle(P(-112, 33), 1000, 64)
+	expect(t, Centroid(circle) == P(-112, 33))
+	expect(t, PointOnSurface(circle) == P(-112, 33))
+	expect(t, Centroid(RO(0, 0, 2, 4)) == P(1, 2))
+
+	empty := expectJSON(t, `{"type":"GeometryCollection","geometries":[]}`,
+		nil)
+	expect(t, Centroid(empty) == empty.Center())
+	expect(t, PointOnSurface(empty) == empty.Center())
+}
+
+func TestCentroidCircleAntimeridian(t *testing.T) {
+	circle := NewCircle(P(179.95, -17), 50000, 64)
+	_, ok := circle.Polygon().(*MultiPolygon)
+	expect(t, ok)
+	expect(t, Centroid(circle) == P(179.95, -17))
+	expect(t, PointOnSurface(circle) == P(179.95, -17))
+	// the circle is an area in a collection with a line
+	gc := new(GeometryCollection)
+	gc.children = []Object{circle, expectJSON(t,
+		`{"type":"LineString","coordinates":[[0,0],[10,0]]}`, nil)}
+	gc.parseInitRectIndex(DefaultParseOptions)
+	expect(t, Centroid(gc) == P(179.95, -17))
+	expect(t, PointOnSurface(gc) == P(179.95, -17))
+}
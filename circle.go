@@ -2,9 +2,11 @@ package geojson
 
 import (
 	"math"
+	"strconv"
 
 	"github.com/tidwall/geojson/geo"
 	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/gjson"
 )
 
 type Circle struct {
@@ -13,6 +15,58 @@ type Circle struct {
 	meters    float64
 	haversine float64
 	steps     int
+	members   string // the kept members of the feature
+}
+
+// circleUnits are the number of meters in one of the "radius_units".
+var circleUnits = map[string]float64{
+	"":    1,
+	"m":   1,
+	"km":  1000,
+	"ft":  0.3048,
+	"mi":  1609.344,
+	"nmi": 1852,
+}
+
+// parseCircle returns the circle for the members of a Circle feature.
+func parseCircle(center geometry.Point, members string, opts *ParseOptions,
+) (*Circle, error) {
+	rradius := gjson.Get(members, "properties.radius")
+	radius := rradius.Float()
+	if opts.RequireValid {
+		if !rradius.Exists() {
+			return nil, errCircleRadiusMissing
+		}
+		if rradius.Type == gjson.String {
+			var err error
+			radius, err = strconv.ParseFloat(rradius.Str, 64)
+			if err != nil {
+				return nil, errCircleRadiusInvalid
+			}
+		} else if rradius.Type != gjson.Number {
+			return nil, errCircleRadiusInvalid
+		}
+		if radius < 0 || math.IsNaN(radius) || math.IsInf(radius, 0) {
+			return nil, errCircleRadiusInvalid
+		}
+	}
+	units := gjson.Get(members, "properties.radius_units").String()
+	meters, ok := opts.CircleUnits[units]
+	if !ok {
+		meters, ok = circleUnits[units]
+		if !ok {
+			return nil, errCircleRadiusUnitsInvalid
+		}
+	}
+	steps := opts.CircleSteps
+	if steps == 0 {
+		steps = 64
+	}
+	g := NewCircle(center, radius*meters, steps)
+	if opts.KeepCircleMembers {
+		g.members = members
+	}
+	return g, nil
 }
 
 // NewCircle returns an circle object
@@ -37,6 +91,12 @@ func (g *Circle) AppendJSON(dst []byte) []byte {
 	dst = appendJSONFloat(dst, g.center.X)
 	dst = append(dst, ',')
 	dst = appendJSONFloat(dst, g.center.Y)
+	if g.members != "" {
+		// the kept members have the radius in its original units
+		dst = append(dst, `]},`...)
+		dst = append(dst, g.members[1:len(g.members)-1]...)
+		return append(dst, '}')
+	}
 	dst = append(dst, `]},"properties":{"type":"Circle","radius":`...)
 	dst = appendJSONFloat(dst, g.meters)
 	dst = append(dst, `,"radius_units":"m"}}`...)
@@ -55,6 +115,20 @@ func (g *Circle) String() string {
 	return string(g.AppendJSON(nil))
 }
 
+// AppendWKT appends the circle polygon as well-known text.
+func (g *Circle) AppendWKT(dst []byte) []byte {
+	return g.getObject().AppendWKT(dst)
+}
+
+func (g *Circle) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+// AppendWKB appends the circle polygon as well-known binary.
+func (g *Circle) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return g.getObject().AppendWKB(dst, opts)
+}
+
 // Meters returns the circle's radius
 func (g *Circle) Meters() float64 {
 	return g.meters
@@ -86,7 +160,8 @@ func (g *Circle) containsPoint(p geometry.Point) bool {
 	return h <= g.haversine
 }
 
-// Contains returns true if the circle contains other object
+// Contains returns true if the circle contains other object. The edges of
+// lines and polygons are great circle arcs, and the distances are exact.
 func (g *Circle) Contains(obj Object) bool {
 	switch other := obj.(type) {
 	case *Point:
@@ -94,7 +169,20 @@ func (g *Circle) Contains(obj Object) bool {
 	case *SimplePoint:
 		return g.containsPoint(other.Center())
 	case *Circle:
-		return other.Distance(g) < (other.meters + g.meters)
+		if g.meters >= 2*circleHemisphere {
+			// the circle covers the whole earth
+			return true
+		}
+		return geoDistancePoints(g.center, other.center)+other.meters <=
+			g.meters
+	case *LineString:
+		return g.containsSeries(other, &other.base)
+	case *Polygon:
+		return g.containsSeries(other, other.base.Exterior)
+	case *Rect:
+		return g.containsSeries(other, other.base)
+	case *Feature:
+		return g.Contains(other.base)
 	case Collection:
 		for _, p := range other.Children() {
 			if !g.Contains(p) {
@@ -108,13 +196,53 @@ func (g *Circle) Contains(obj Object) bool {
 	}
 }
 
-// Intersects returns true the circle intersects other object
+// circleHemisphere is the radius of a circle that covers a hemisphere.
+var circleHemisphere = geo.DistanceTo(0, 0, 0, 90)
+
+// containsSeries returns true if the circle contains all of the points, and
+// all of the edges between them, of the series of an object.
+func (g *Circle) containsSeries(obj Object, series geometry.Series) bool {
+	if series.Empty() {
+		return false
+	}
+	if g.meters >= circleHemisphere {
+		// Circles that are larger than a hemisphere are not convex, and
+		// the edges of the series may leave the circle between points that
+		// are inside, so using polygon approximation.
+		return g.getObject().Contains(obj)
+	}
+	if !g.Rect().ContainsRect(series.Rect()) {
+		return false
+	}
+	n := series.NumPoints()
+	for i := 0; i < n; i++ {
+		if !g.containsPoint(series.PointAt(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects returns true the circle intersects other object. The edges of
+// lines and polygons are great circle arcs, and the distances are exact.
 func (g *Circle) Intersects(obj Object) bool {
 	switch other := obj.(type) {
 	case *Point:
 		return g.containsPoint(other.Center())
+	case *SimplePoint:
+		return g.containsPoint(other.Center())
 	case *Circle:
-		return other.Distance(g) <= (other.meters + g.meters)
+		return geoDistancePoints(g.center, other.center) <=
+			(other.meters + g.meters)
+	case *LineString:
+		return g.intersectsRect(other.Rect()) &&
+			geoDistancePointLine(g.center, &other.base) <= g.meters
+	case *Polygon:
+		return g.intersectsRect(other.Rect()) &&
+			geoDistancePointPoly(g.center, &other.base) <= g.meters
+	case *Rect:
+		return g.intersectsRect(other.base) &&
+			geoDistancePointRect(g.center, other.base) <= g.meters
 	case Collection:
 		for _, p := range other.Children() {
 			if g.Intersects(p) {
@@ -130,6 +258,35 @@ func (g *Circle) Intersects(obj Object) bool {
 	}
 }
 
+// intersectsRect returns true if the rectangles of the circle intersect the
+// rectangle, which skips the distance calculations for objects that are far
+// away.
+func (g *Circle) intersectsRect(rect geometry.Rect) bool {
+	for _, crect := range g.Rects() {
+		if crect.IntersectsRect(rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinPoly returns true if the circle is inside of the polygon, which is
+// when the center is inside and no edge of the polygon is within the radius.
+func (g *Circle) withinPoly(poly *geometry.Poly) bool {
+	if g.meters >= circleHemisphere {
+		return g.getObject().Spatial().WithinPoly(poly)
+	}
+	if !poly.ContainsPoint(g.center) {
+		return false
+	}
+	for _, ring := range polyRings(poly) {
+		if geoDistancePointSeries(g.center, ring) < g.meters {
+			return false
+		}
+	}
+	return true
+}
+
 func (g *Circle) Empty() bool {
 	return false
 }
@@ -147,12 +304,40 @@ func (g *Circle) NumPoints() int {
 	return 1
 }
 
+// Distance returns the distance in meters from the edge of the circle to the
+// other object, or zero when they intersect.
 func (g *Circle) Distance(other Object) float64 {
-	return g.getObject().Distance(other)
+	return math.Max(other.Spatial().DistancePoint(g.center)-g.meters, 0)
 }
 
+// Rect returns the rectangle surrounding the circle. For a circle that
+// crosses the antimeridian, it's the union of the two rectangles of Rects,
+// which spans all longitudes. Collections use Rects to index and search
+// such a circle.
 func (g *Circle) Rect() geometry.Rect {
-	return g.getObject().Rect()
+	rects := g.Rects()
+	rect := rects[0]
+	for _, other := range rects[1:] {
+		rect = unionRects(rect, other)
+	}
+	return rect
+}
+
+// Rects returns the rectangles surrounding the circle. A circle that crosses
+// the antimeridian has two rectangles, one on each side.
+func (g *Circle) Rects() []geometry.Rect {
+	if g.meters <= 0 {
+		return []geometry.Rect{{Min: g.center, Max: g.center}}
+	}
+	meters := geo.NormalizeDistance(g.meters)
+	var rects []geometry.Rect
+	for _, rect := range geo.RectsFromCenter(g.center.Y, g.center.X, meters) {
+		rects = append(rects, geometry.Rect{
+			Min: geometry.Point{X: rect.MinLon, Y: rect.MinLat},
+			Max: geometry.Point{X: rect.MaxLon, Y: rect.MaxLat},
+		})
+	}
+	return rects
 }
 
 func (g *Circle) Spatial() Spatial {
@@ -182,38 +367,176 @@ func makeCircleObject(center geometry.Point, meters float64, steps int) Object {
 		return gPoly
 	}
 	meters = geo.NormalizeDistance(meters)
-	points := make([]geometry.Point, 0, steps+1)
-
-	// calc the four corners
-	maxY, _ := geo.DestinationPoint(center.Y, center.X, meters, 0)
-	_, maxX := geo.DestinationPoint(center.Y, center.X, meters, 90)
-	minY, _ := geo.DestinationPoint(center.Y, center.X, meters, 180)
-	_, minX := geo.DestinationPoint(center.Y, center.X, meters, 270)
-
-	// TODO: detect of pole and antimeridian crossing and generate a
-	// valid multigeometry
-
-	// use the half width of the lat and lon
-	lons := (maxX - minX) / 2
-	lats := (maxY - minY) / 2
-
-	// generate the
-	for th := 0.0; th <= 360.0; th += 360.0 / float64(steps) {
-		radians := (math.Pi / 180) * th
-		x := center.X + lons*math.Cos(radians)
-		y := center.Y + lats*math.Sin(radians)
+	noIndex := &geometry.IndexOptions{Kind: geometry.None}
+	world := []geometry.Point{
+		{X: -180, Y: -90}, {X: 180, Y: -90}, {X: 180, Y: 90}, {X: -180, Y: 90},
+		{X: -180, Y: -90},
+	}
+	antipode := geometry.Point{
+		X: math.Remainder(center.X+180, 360), Y: -center.Y,
+	}
+	north := geo.DistanceTo(center.Y, center.X, 90, center.X) < meters
+	south := geo.DistanceTo(center.Y, center.X, -90, center.X) < meters
+	if north && south {
+		// The circle covers both poles, which makes it the whole world with
+		// the circle around the antipode as holes.
+		var holes [][]geometry.Point
+		remain := geo.DistanceTo(center.Y, center.X, antipode.Y, antipode.X) -
+			meters
+		if remain > 0 {
+			holes = makeCircleRings(antipode, remain, steps, 0)
+		}
+		return NewPolygon(geometry.NewPoly(world, holes, noIndex))
+	}
+	var pole float64
+	if north {
+		pole = 90
+	} else if south {
+		pole = -90
+	}
+	rings := makeCircleRings(center, meters, steps, pole)
+	if len(rings) == 1 {
+		return NewPolygon(geometry.NewPoly(rings[0], nil, noIndex))
+	}
+	polys := make([]*geometry.Poly, len(rings))
+	for i, ring := range rings {
+		polys[i] = geometry.NewPoly(ring, nil, noIndex)
+	}
+	return NewMultiPolygon(polys)
+}
+
+// makeCircleRings returns the rings of a circle, split at the antimeridian
+// when needed. The pole is 90 or -90 when the circle encloses the north or
+// south pole, and zero otherwise.
+func makeCircleRings(center geometry.Point, meters float64, steps int,
+	pole float64,
+) [][]geometry.Point {
+	// generate the points counter-clockwise, starting east of the center,
+	// while keeping the longitudes continuous.
+	points := make([]geometry.Point, 0, steps+4)
+	prev := center.X
+	for i := 0; i < steps; i++ {
+		bearing := 90 - 360*float64(i)/float64(steps)
+		y, x := geo.DestinationPoint(center.Y, center.X, meters, bearing)
+		x = prev + math.Remainder(x-prev, 360)
 		points = append(points, geometry.Point{X: x, Y: y})
+		prev = x
+	}
+	if pole != 0 {
+		return [][]geometry.Point{makePoleRing(points, pole)}
 	}
 	// add last connecting point, make a total of steps+1
 	points = append(points, points[0])
+	var minX, maxX float64 = points[0].X, points[0].X
+	for _, point := range points {
+		minX = math.Min(minX, point.X)
+		maxX = math.Max(maxX, point.X)
+	}
+	var edge float64
+	if maxX > 180 {
+		edge = 180
+	} else if minX < -180 {
+		edge = -180
+	} else {
+		return [][]geometry.Point{points}
+	}
+	// the part beyond the edge is moved to the other side of the world
+	inner := clipRing(points, edge, edge > 0)
+	outer := clipRing(points, edge, edge < 0)
+	for i := range outer {
+		outer[i].X -= 2 * edge
+	}
+	var rings [][]geometry.Point
+	for _, ring := range [][]geometry.Point{inner, outer} {
+		if len(ring) >= 4 {
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
 
-	return NewPolygon(
-		geometry.NewPoly(points, nil, &geometry.IndexOptions{
-			Kind: geometry.None,
-		}),
+// makePoleRing returns the ring of a circle that encloses a pole. The points
+// wind once around the pole, so the ring is rotated to start and end at the
+// antimeridian and then closed along the pole.
+func makePoleRing(points []geometry.Point, pole float64) []geometry.Point {
+	n := len(points)
+	wind := points[n-1].X + math.Remainder(points[0].X-points[n-1].X, 360) -
+		points[0].X
+	at := func(i int) geometry.Point {
+		point := points[i%n]
+		point.X += wind * float64(i/n)
+		return point
+	}
+	// find the first segment that crosses the antimeridian
+	var start geometry.Point
+	var idx int
+	for i := 0; i < n; i++ {
+		a, b := at(i), at(i+1)
+		lo, hi := math.Min(a.X, b.X), math.Max(a.X, b.X)
+		x := 360*math.Floor((hi-180)/360) + 180
+		if x < lo {
+			continue
+		}
+		var t float64
+		if b.X != a.X {
+			t = (x - a.X) / (b.X - a.X)
+		}
+		start = geometry.Point{X: x, Y: a.Y + t*(b.Y-a.Y)}
+		idx = i + 1
+		break
+	}
+	ring := make([]geometry.Point, 0, n+5)
+	ring = append(ring, start)
+	for i := idx; i < idx+n; i++ {
+		if point := at(i); point != ring[len(ring)-1] {
+			ring = append(ring, point)
+		}
+	}
+	end := geometry.Point{X: start.X + wind, Y: start.Y}
+	if end != ring[len(ring)-1] {
+		ring = append(ring, end)
+	}
+	shift := -180 - start.X
+	if wind < 0 {
+		shift = 180 - start.X
+	}
+	for i := range ring {
+		ring[i].X += shift
+	}
+	ring = append(ring,
+		geometry.Point{X: ring[len(ring)-1].X, Y: pole},
+		geometry.Point{X: ring[0].X, Y: pole},
+		ring[0],
 	)
+	return ring
+}
+
+// clipRing returns the part of a closed ring that is on the left or right of
+// the vertical line at x.
+func clipRing(ring []geometry.Point, x float64, left bool) []geometry.Point {
+	inside := func(point geometry.Point) bool {
+		if left {
+			return point.X <= x
+		}
+		return point.X >= x
+	}
+	var clipped []geometry.Point
+	for i := 0; i < len(ring)-1; i++ {
+		a, b := ring[i], ring[i+1]
+		if inside(a) {
+			clipped = append(clipped, a)
+		}
+		if inside(a) != inside(b) && a.X != x && b.X != x {
+			t := (x - a.X) / (b.X - a.X)
+			clipped = append(clipped, geometry.Point{X: x, Y: a.Y + t*(b.Y-a.Y)})
+		}
+	}
+	if len(clipped) > 0 {
+		clipped = append(clipped, clipped[0])
+	}
+	return clipped
 }
 
 func (g *Circle) Members() string {
-	return ""
+	return g.members
 }
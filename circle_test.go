@@ -3,6 +3,7 @@ package geojson
 import (
 	"testing"
 
+	"github.com/tidwall/geojson/geo"
 	"github.com/tidwall/geojson/geometry"
 )
 
@@ -29,6 +30,82 @@ func TestCircleNew(t *testing.T) {
 
 }
 
+func TestCircleParse(t *testing.T) {
+	circle := func(radius, units string) string {
+		json := `{"type":"Feature","geometry":{"type":"Point",` +
+			`"coordinates":[-112,33]},"properties":{"type":"Circle"`
+		if radius != "" {
+			json += `,"radius":` + radius
+		}
+		if units != "" {
+			json += `,"radius_units":"` + units + `"`
+		}
+		return json + `}}`
+	}
+	parse := func(json string, opts *ParseOptions) *Circle {
+		t.Helper()
+		g, err := Parse(json, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g.(*Circle)
+	}
+	meters := func(json string, opts *ParseOptions) float64 {
+		t.Helper()
+		return parse(json, opts).Meters()
+	}
+	expect(t, meters(circle("2", "km"), nil) == 2000)
+	expect(t, meters(circle("10", "ft"), nil) == 3.048)
+	expect(t, meters(circle("2", "mi"), nil) == 3218.688)
+	expect(t, meters(circle("2", "nmi"), nil) == 3704)
+	expectJSON(t, circle("2", "yd"), errCircleRadiusUnitsInvalid)
+	opts := *DefaultParseOptions
+	opts.CircleUnits = map[string]float64{"yd": 0.9144, "km": 1}
+	expect(t, meters(circle("2", "yd"), &opts) == 1.8288)
+	expect(t, meters(circle("2", "km"), &opts) == 2)
+	expect(t, meters(circle("2", "mi"), &opts) == 3218.688)
+
+	// steps
+	expect(t, parse(circle("1000", ""), nil).getObject().NumPoints() == 65)
+	opts = *DefaultParseOptions
+	opts.CircleSteps = 256
+	expect(t, parse(circle("1000", ""), &opts).getObject().NumPoints() == 257)
+	expect(t, parse(circle("1000", ""), &ParseOptions{}).getObject().
+		NumPoints() == 65)
+
+	// validation
+	// invalid radii are allowed without RequireValid
+	for _, radius := range []string{"", "-1", `"-1"`, `"NaN"`, `"abc"`,
+		"null", "true", "{}"} {
+		parse(circle(radius, ""), nil)
+	}
+	opts = *DefaultParseOptions
+	opts.RequireValid = true
+	expectJSONOpts(t, circle("", ""), errCircleRadiusMissing, &opts)
+	for _, radius := range []string{"-1", `"-1"`, `"NaN"`, `"abc"`, "null",
+		"true", "{}", `"Inf"`} {
+		expectJSONOpts(t, circle(radius, ""), errCircleRadiusInvalid, &opts)
+	}
+	expect(t, meters(circle(`"1000"`, ""), &opts) == 1000)
+	expect(t, meters(circle("0", ""), &opts) == 0)
+
+	// keeping the members
+	json := `{"type":"Feature","id":"a1","geometry":{"type":"Point",` +
+		`"coordinates":[-112,33]},"properties":{"type":"Circle",` +
+		`"radius":2,"radius_units":"mi","name":"zone"}}`
+	expectJSON(t, json, `{"type":"Feature","geometry":{"type":"Point",`+
+		`"coordinates":[-112,33]},"properties":{"type":"Circle",`+
+		`"radius":3218.688,"radius_units":"m"}}`)
+	opts = *DefaultParseOptions
+	opts.KeepCircleMembers = true
+	g := expectJSONOpts(t, json, `{"type":"Feature","geometry":{"type":"Point",`+
+		`"coordinates":[-112,33]},"id":"a1","properties":{"type":"Circle",`+
+		`"radius":2,"radius_units":"mi","name":"zone"}}`, &opts)
+	expect(t, g.(*Circle).Meters() == 3218.688)
+	expect(t, g.Members() == `{"id":"a1","properties":{"type":"Circle",`+
+		`"radius":2,"radius_units":"mi","name":"zone"}}`)
+}
+
 func TestCircleContains(t *testing.T) {
 	g := NewCircle(P(-122.4412, 37.7335), 1000, 64)
 	expect(t, g.Contains(PO(-122.4412, 37.7335)))
@@ -179,3 +256,143 @@ func TestPointCircle(t *testing.T) {
 		t.Fatal("expected true")
 	}
 }
+
+func TestCircleAntimeridian(t *testing.T) {
+	// Fiji
+	g := NewCircle(P(179, -17), 300000, 64)
+	mp, ok := g.Polygon().(*MultiPolygon)
+	expect(t, ok)
+	expect(t, len(mp.Children()) == 2)
+	rect := g.Rect()
+	expect(t, rect.Min.X >= -180 && rect.Max.X <= 180)
+	rects := g.Rects()
+	expect(t, len(rects) == 2)
+	expect(t, rects[0].Max.X == 180 && rects[1].Min.X == -180)
+	expect(t, rects[0].ContainsRect(mp.Children()[0].Rect()) ||
+		rects[1].ContainsRect(mp.Children()[0].Rect()))
+	expect(t, g.Valid())
+	expect(t, g.Contains(PO(179.9, -17)))
+	expect(t, g.Contains(PO(-179.9, -17)))
+	expect(t, g.Intersects(PO(-178.5, -17)))
+	expect(t, !g.Intersects(PO(0, -17)))
+	expect(t, !g.Intersects(PO(-175, -17)))
+	expect(t, g.Intersects(RO(-179, -18, -178.5, -16)))
+	expect(t, !g.Intersects(RO(-170, -18, -169, -16)))
+	expect(t, g.Contains(RO(-179.5, -17.5, -179, -16.5)))
+	expect(t, g.Contains(RO(179, -17.5, 179.5, -16.5)))
+
+	// Bering Strait, crossing from the west
+	g = NewCircle(P(-170, 65), 800000, 64)
+	mp, ok = g.Polygon().(*MultiPolygon)
+	expect(t, ok)
+	expect(t, len(mp.Children()) == 2)
+	expect(t, g.Intersects(PO(178, 65)))
+	expect(t, !g.Intersects(PO(170, 65)))
+
+	// not crossing
+	g = NewCircle(P(170, -17), 300000, 64)
+	_, ok = g.Polygon().(*Polygon)
+	expect(t, ok)
+	expect(t, len(g.Rects()) == 1)
+	expect(t, g.Rects()[0] == g.Rect())
+}
+
+func TestCirclePoles(t *testing.T) {
+	for _, lat := range []float64{89, -89} {
+		g := NewCircle(P(10, lat), 300000, 64)
+		_, ok := g.Polygon().(*Polygon)
+		expect(t, ok)
+		rect := g.Rect()
+		expect(t, rect.Min.X == -180 && rect.Max.X == 180)
+		if lat > 0 {
+			expect(t, rect.Max.Y == 90)
+		} else {
+			expect(t, rect.Min.Y == -90)
+		}
+		expect(t, g.Intersects(PO(-170, lat)))
+		expect(t, g.Intersects(PO(190-360, lat*1.005)))
+		expect(t, g.Intersects(RO(100, lat-0.5, 110, lat+0.5)))
+		expect(t, !g.Intersects(PO(10, lat/2)))
+		expect(t, g.Contains(RO(-100, lat-0.1, 100, lat+0.1)))
+	}
+	// both poles are inside, only the area around the antipode is not
+	g := NewCircle(P(0, 10), 15000000, 64)
+	expect(t, g.Intersects(PO(0, 90)))
+	expect(t, g.Intersects(PO(0, -90)))
+	expect(t, g.Intersects(PO(90, 0)))
+	expect(t, !g.Intersects(PO(180, -10)))
+	expect(t, !g.Intersects(PO(-179, -10)))
+	expect(t, !g.Intersects(PO(179, -10)))
+	// the whole world
+	g = NewCircle(P(0, 10), 20100000, 64)
+	expect(t, g.Intersects(PO(179.9, -10.1)))
+	expect(t, g.Contains(RO(-170, -80, 170, 80)))
+}
+
+func TestCircleExact(t *testing.T) {
+	center := P(-112, 33)
+	g := NewCircle(center, 1000, 64)
+	at := func(meters, bearing float64) geometry.Point {
+		lat, lon := geo.DestinationPoint(center.Y, center.X, meters, bearing)
+		return P(lon, lat)
+	}
+	// the bearings halfway between the points of the polygon approximation,
+	// where it is the furthest inside of the circle
+	b1, b2 := 90+360/64.0/2, 90+360/64.0*3/2
+
+	// circles
+	expect(t, g.Contains(NewCircle(at(400, 0), 500, 64)))
+	expect(t, !g.Contains(NewCircle(at(800, 0), 500, 64)))
+	expect(t, g.Intersects(NewCircle(at(800, 0), 500, 64)))
+	expect(t, !g.Intersects(NewCircle(at(1600, 0), 500, 64)))
+	expect(t, NewCircle(center, 21000000, 64).Contains(
+		NewCircle(P(100, -40), 1000, 64)))
+
+	// lines
+	line := LO([]geometry.Point{at(999.5, b1), at(999.5, b2)})
+	expect(t, g.Contains(line))
+	expect(t, !g.getObject().Contains(line))
+	expect(t, !g.Contains(LO([]geometry.Point{at(999.5, b1), at(1000.5, b2)})))
+	expect(t, g.Intersects(LO([]geometry.Point{at(999.5, b1), at(5000, b1)})))
+	expect(t, !g.Intersects(LO([]geometry.Point{at(1000.5, b1),
+		at(5000, b1)})))
+
+	// polygons and rects
+	poly := PPO([]geometry.Point{
+		at(999.5, b1), at(999.5, b2), at(500, 180), at(999.5, b1),
+	}, nil)
+	expect(t, g.Contains(poly))
+	expect(t, !g.getObject().Contains(poly))
+	expect(t, poly.Within(g))
+	expect(t, g.Intersects(poly) && poly.Intersects(g))
+	far := PPO([]geometry.Point{
+		at(1000.5, b1), at(2000, b1), at(2000, b2), at(1000.5, b1),
+	}, nil)
+	expect(t, !g.Intersects(far) && !far.Intersects(g))
+	near := PPO([]geometry.Point{
+		at(999.5, b1), at(2000, b1), at(2000, b2), at(999.5, b1),
+	}, nil)
+	expect(t, g.Intersects(near) && near.Intersects(g))
+	expect(t, !g.getObject().Intersects(near))
+	expect(t, g.Intersects(NewFeature(near, "")))
+	expect(t, g.Contains(NewFeature(poly, "")))
+
+	// a polygon or rect that contains the circle
+	n, e := at(1000, 0), at(1000, 90)
+	s, w := at(1000, 180), at(1000, 270)
+	rect := RO(w.X-0.0001, s.Y-0.0001, e.X+0.0001, n.Y+0.0001)
+	expect(t, rect.Contains(g))
+	expect(t, g.Within(rect))
+	expect(t, g.Intersects(rect) && rect.Intersects(g))
+	expect(t, !RO(w.X+0.0001, s.Y-0.0001, e.X+0.0001, n.Y+0.0001).Contains(g))
+	expect(t, PPO([]geometry.Point{
+		P(w.X-0.0001, s.Y-0.0001), P(e.X+0.0001, s.Y-0.0001),
+		P(e.X+0.0001, n.Y+0.0001), P(w.X-0.0001, n.Y+0.0001),
+		P(w.X-0.0001, s.Y-0.0001),
+	}, nil).Contains(g))
+	expect(t, !PPO([]geometry.Point{
+		P(w.X-0.0001, s.Y+0.0001), P(e.X+0.0001, s.Y+0.0001),
+		P(e.X+0.0001, n.Y+0.0001), P(w.X-0.0001, n.Y+0.0001),
+		P(w.X-0.0001, s.Y+0.0001),
+	}, nil).Contains(g))
+}
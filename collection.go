@@ -1,6 +1,9 @@
 package geojson
 
 import (
+	"container/heap"
+	"math"
+
 	"github.com/tidwall/geojson/geometry"
 	"github.com/tidwall/rtree"
 )
@@ -9,6 +12,7 @@ type collection struct {
 	children []Object
 	extra    *extra
 	tree     *rtree.RTree
+	split    bool // a child has more than one rectangle in the tree
 	prect    geometry.Rect
 	pempty   bool
 }
@@ -36,11 +40,23 @@ func (g *collection) Base() []Object {
 
 func (g *collection) Search(rect geometry.Rect, iter func(child Object) bool) {
 	if g.tree != nil {
+		// a child with more than one rectangle may be found more than once
+		var seen map[Object]bool
+		if g.split {
+			seen = make(map[Object]bool)
+		}
 		g.tree.Search(
 			[2]float64{rect.Min.X, rect.Min.Y},
 			[2]float64{rect.Max.X, rect.Max.Y},
 			func(_, _ [2]float64, value interface{}) bool {
-				return iter(value.(Object))
+				child := value.(Object)
+				if seen != nil {
+					if seen[child] {
+						return true
+					}
+					seen[child] = true
+				}
+				return iter(child)
 			},
 		)
 	} else {
@@ -48,8 +64,11 @@ func (g *collection) Search(rect geometry.Rect, iter func(child Object) bool) {
 			if child.Empty() {
 				continue
 			}
-			if child.Rect().IntersectsRect(rect) {
-				if !iter(child) {
+			for _, crect := range objectRects(child) {
+				if crect.IntersectsRect(rect) {
+					if !iter(child) {
+						return
+					}
 					break
 				}
 			}
@@ -57,6 +76,44 @@ func (g *collection) Search(rect geometry.Rect, iter func(child Object) bool) {
 	}
 }
 
+// SearchRects iterates over the children that intersect any of the rects.
+// Each child is visited at most once.
+func (g *collection) SearchRects(rects []geometry.Rect,
+	iter func(child Object) bool,
+) {
+	if len(rects) == 1 {
+		g.Search(rects[0], iter)
+		return
+	}
+	seen := make(map[Object]bool)
+	for _, rect := range rects {
+		done := false
+		g.Search(rect, func(child Object) bool {
+			if seen[child] {
+				return true
+			}
+			seen[child] = true
+			if !iter(child) {
+				done = true
+				return false
+			}
+			return true
+		})
+		if done {
+			return
+		}
+	}
+}
+
+// objectRects returns the rectangles surrounding an object, which is more
+// than one for circles that cross the antimeridian.
+func objectRects(obj Object) []geometry.Rect {
+	if circle, ok := obj.(*Circle); ok {
+		return circle.Rects()
+	}
+	return []geometry.Rect{obj.Rect()}
+}
+
 func (g *collection) Empty() bool {
 	return g.pempty
 }
@@ -78,10 +135,22 @@ func (g *collection) AppendJSON(dst []byte) []byte {
 	return append(dst, "null"...)
 }
 
+func (g *collection) AppendWKT(dst []byte) []byte {
+	return appendWKTGeometries(dst, g.children)
+}
+
+func (g *collection) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBGeometries(dst, opts, g.children)
+}
+
 func (g *collection) JSON() string {
 	return string(g.AppendJSON(nil))
 }
 
+func (g *collection) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
 func (g *collection) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
@@ -106,7 +175,7 @@ func (g *collection) Contains(obj Object) bool {
 			return true
 		}
 		var geomContained bool
-		g.Search(geom.Rect(), func(child Object) bool {
+		g.SearchRects(objectRects(geom), func(child Object) bool {
 			if child.Contains(geom) {
 				// found a child object that contains geom, end inner loop
 				geomContained = true
@@ -196,7 +265,7 @@ func (g *collection) Intersects(obj Object) bool {
 			// ignore the empties
 			return true
 		}
-		g.Search(geom.Rect(), func(child Object) bool {
+		g.SearchRects(objectRects(geom), func(child Object) bool {
 			if child.Intersects(geom) {
 				intersects = true
 				return false
@@ -291,30 +360,143 @@ func (g *collection) parseInitRectIndex(opts *ParseOptions) {
 			if child.Empty() {
 				continue
 			}
-			rect := child.Rect()
-			g.tree.Insert(
-				[2]float64{rect.Min.X, rect.Min.Y},
-				[2]float64{rect.Max.X, rect.Max.Y},
-				child,
-			)
+			// a circle that crosses the antimeridian is inserted with a
+			// rectangle on each side
+			rects := objectRects(child)
+			if len(rects) > 1 {
+				g.split = true
+			}
+			for _, rect := range rects {
+				g.tree.Insert(
+					[2]float64{rect.Min.X, rect.Min.Y},
+					[2]float64{rect.Max.X, rect.Max.Y},
+					child,
+				)
+			}
 		}
 	}
 }
 
 func (g *collection) Distance(obj Object) float64 {
-	return obj.Spatial().DistancePoint(g.Center())
+	return g.distance(obj.Rect(), func(child Object) float64 {
+		return child.Distance(obj)
+	})
 }
 func (g *collection) DistancePoint(point geometry.Point) float64 {
-	return geoDistancePoints(g.Center(), point)
+	return g.distance(point.Rect(), func(child Object) float64 {
+		return child.Spatial().DistancePoint(point)
+	})
 }
 func (g *collection) DistanceRect(rect geometry.Rect) float64 {
-	return geoDistancePoints(g.Center(), rect.Center())
+	return g.distance(rect, func(child Object) float64 {
+		return child.Spatial().DistanceRect(rect)
+	})
 }
 func (g *collection) DistanceLine(line *geometry.Line) float64 {
-	return geoDistancePoints(g.Center(), line.Rect().Center())
+	return g.distance(line.Rect(), func(child Object) float64 {
+		return child.Spatial().DistanceLine(line)
+	})
 }
 func (g *collection) DistancePoly(poly *geometry.Poly) float64 {
-	return geoDistancePoints(g.Center(), poly.Rect().Center())
+	return g.distance(poly.Rect(), func(child Object) float64 {
+		return child.Spatial().DistancePoly(poly)
+	})
+}
+
+// distance returns the smallest distance to the non-empty children. The
+// children are visited nearest first, and the children whose rectangles
+// can't be nearer than the best distance are skipped.
+func (g *collection) distance(rect geometry.Rect,
+	dist func(child Object) float64,
+) float64 {
+	best := math.Inf(1)
+	if g.tree == nil {
+		for _, child := range g.children {
+			if child.Empty() || objectDistanceBound(rect, child) >= best {
+				continue
+			}
+			if d := dist(child); d < best {
+				best = d
+				if best == 0 {
+					break
+				}
+			}
+		}
+		return geoDistanceFallback(best, g.Rect(), rect)
+	}
+	// walk the tree nodes and children in the order of their lower bounds
+	var queue distanceQueue
+	push := func(parent interface{}) {
+		for _, c := range g.tree.Children(parent, nil) {
+			crect := geometry.Rect{
+				Min: geometry.Point{X: c.Min[0], Y: c.Min[1]},
+				Max: geometry.Point{X: c.Max[0], Y: c.Max[1]},
+			}
+			heap.Push(&queue, distanceItem{
+				bound: geoRectDistanceBound(rect, crect),
+				data:  c.Data,
+				item:  c.Item,
+			})
+		}
+	}
+	push(nil)
+	var seen map[Object]bool
+	if g.split {
+		seen = make(map[Object]bool)
+	}
+	for queue.Len() > 0 && best > 0 {
+		next := heap.Pop(&queue).(distanceItem)
+		if next.bound >= best {
+			break
+		}
+		if !next.item {
+			push(next.data)
+			continue
+		}
+		child := next.data.(Object)
+		if seen != nil {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+		}
+		if d := dist(child); d < best {
+			best = d
+		}
+	}
+	return geoDistanceFallback(best, g.Rect(), rect)
+}
+
+// objectDistanceBound returns the lower bound of the distance between a
+// rectangle and the rectangles of an object.
+func objectDistanceBound(rect geometry.Rect, obj Object) float64 {
+	bound := math.Inf(1)
+	for _, orect := range objectRects(obj) {
+		bound = math.Min(bound, geoRectDistanceBound(rect, orect))
+	}
+	return bound
+}
+
+// distanceItem is a tree node or a child, with the lower bound of its
+// distance.
+type distanceItem struct {
+	bound float64
+	data  interface{}
+	item  bool
+}
+
+// distanceQueue is a min heap of tree nodes and children ordered by their
+// lower bounds.
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int            { return len(q) }
+func (q distanceQueue) Less(i, j int) bool  { return q[i].bound < q[j].bound }
+func (q distanceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x interface{}) { *q = append(*q, x.(distanceItem)) }
+func (q *distanceQueue) Pop() interface{} {
+	item := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return item
 }
 
 func (g *collection) Members() string {
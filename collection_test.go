@@ -3,6 +3,7 @@ package geojson
 import (
 	"fmt"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"os"
 	"sort"
@@ -134,3 +135,74 @@ func TestCollectionUSASubset(t *testing.T) {
 	})
 
 }
+
+func TestCollectionSearchRects(t *testing.T) {
+	json := `{"type":"MultiPoint","coordinates":[
+		[179.9,-17],[-179.9,-17],[0,-17],[90,-17],[-90,-17],[179.9,10]
+	]}`
+	circle := NewCircle(P(179.95, -17), 50000, 64)
+	rects := circle.Rects()
+	expect(t, len(rects) == 2)
+	for _, index := range []bool{false, true} {
+		c := parseCollection(t, json, index)
+		var visited []Object
+		c.SearchRects(rects, func(child Object) bool {
+			visited = append(visited, child)
+			return true
+		})
+		expect(t, len(visited) == 2)
+		var count int
+		c.SearchRects(rects, func(child Object) bool {
+			count++
+			return false
+		})
+		expect(t, count == 1)
+		// overlapping rects visit each child once
+		count = 0
+		c.SearchRects([]geometry.Rect{R(170, -20, 180, -10),
+			R(175, -20, 180, -10)},
+			func(child Object) bool {
+				count++
+				return true
+			},
+		)
+		expect(t, count == 1)
+		expect(t, c.(Object).Intersects(circle))
+		expect(t, !c.(Object).Intersects(NewCircle(P(179.95, 0), 50000, 64)))
+	}
+}
+
+func TestCollectionCircleRects(t *testing.T) {
+	// a circle that crosses the antimeridian is indexed with its two
+	// rectangles, and not with a rectangle that spans all longitudes
+	circle := `{"type":"Feature","geometry":{"type":"Point",` +
+		`"coordinates":[179.95,-17]},"properties":{"type":"Circle",` +
+		`"radius":50000,"radius_units":"m"}}`
+	features := []string{circle}
+	for i := 0; i < 5; i++ {
+		features = append(features, fmt.Sprintf(`{"type":"Feature",`+
+			`"geometry":{"type":"Point","coordinates":[%d,40]},`+
+			`"properties":{}}`, i-2))
+	}
+	json := `{"type":"FeatureCollection","features":[` +
+		strings.Join(features, ",") + `]}`
+	for _, index := range []bool{false, true} {
+		c := parseCollection(t, json, index)
+		expect(t, c.Indexed() == index)
+		var count int
+		c.Search(R(-1, -20, 1, -10), func(child Object) bool {
+			count++
+			return true
+		})
+		expect(t, count == 0)
+		c.Search(R(-180, -20, 180, -10), func(child Object) bool {
+			count++
+			return true
+		})
+		expect(t, count == 1)
+		point := PO(0, -17)
+		expectDistance(t, c.(Object).Distance(point),
+			math.Min(point.Distance(PO(0, 40)), point.Distance(
+				NewCircle(P(179.95, -17), 50000, 64))))
+	}
+}
@@ -0,0 +1,325 @@
+package geojson
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/pretty"
+)
+
+// DecodeError is an error for a feature that was read by a Decoder, or for a
+// record that was read by a SequenceReader.
+type DecodeError struct {
+	// Index is the position of the feature in the "features" array, or of
+	// the record in the sequence.
+	Index int
+	Err   error
+	kind  string // "record" for a sequence, otherwise a feature
+}
+
+func (e *DecodeError) Error() string {
+	kind := e.kind
+	if kind == "" {
+		kind = "feature"
+	}
+	return fmt.Sprintf("%s %d: %v", kind, e.Index, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	decodeStart = iota
+	decodeMembers
+	decodeFeatures
+)
+
+// Decoder reads the features of a GeoJSON FeatureCollection from a stream,
+// one at a time, without reading the whole collection into memory.
+type Decoder struct {
+	rd          *bufio.Reader
+	opts        *ParseOptions
+	state       int
+	first       bool   // the next member or feature is the first
+	index       int    // index of the next feature
+	members     []byte // foreign members of the collection
+	hasType     bool
+	hasFeatures bool
+	buf         []byte
+	err         error
+}
+
+// NewDecoder returns a decoder that reads a FeatureCollection from r. The
+// features are parsed using the provided options.
+func NewDecoder(r io.Reader, opts *ParseOptions) *Decoder {
+	if opts == nil {
+		opts = DefaultParseOptions
+	}
+	return &Decoder{rd: bufio.NewReader(r), opts: opts}
+}
+
+// Members returns the foreign members of the collection, such as "bbox",
+// that have been read so far, as a json object. The members that follow the
+// "features" array are available once Next returns io.EOF.
+func (d *Decoder) Members() string {
+	if len(d.members) == 0 {
+		return ""
+	}
+	return string(d.members) + "}"
+}
+
+// Next returns the next feature of the collection. It returns io.EOF when
+// there are no more features. An error for a feature is a *DecodeError, and
+// the decoder can continue with the next feature after a feature that is not
+// valid. Other errors stop the decoder.
+func (d *Decoder) Next() (Object, error) {
+	for d.err == nil {
+		switch d.state {
+		case decodeStart:
+			if c, err := d.skipSpace(); err != nil || c != '{' {
+				d.fail(err)
+				break
+			}
+			d.state, d.first = decodeMembers, true
+		case decodeMembers:
+			d.nextMember()
+		case decodeFeatures:
+			if obj, err := d.nextFeature(); obj != nil || err != nil {
+				return obj, err
+			}
+		}
+	}
+	return nil, d.err
+}
+
+// nextMember reads the next member of the collection, or the end of the
+// collection.
+func (d *Decoder) nextMember() {
+	c, err := d.skipSpace()
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	if c == '}' {
+		switch {
+		case !d.hasType:
+			d.err = errTypeMissing
+		case !d.hasFeatures:
+			d.err = errFeaturesMissing
+		default:
+			d.err = io.EOF
+		}
+		return
+	}
+	if !d.first {
+		if c != ',' {
+			d.fail(nil)
+			return
+		}
+		if c, err = d.skipSpace(); err != nil {
+			d.fail(err)
+			return
+		}
+	}
+	d.first = false
+	if c != '"' {
+		d.fail(nil)
+		return
+	}
+	d.buf, err = d.readValue(d.buf[:0], c)
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	key := gjson.ParseBytes(d.buf).String()
+	rawKey := pretty.Ugly(d.buf)
+	if c, err = d.skipSpace(); err != nil || c != ':' {
+		d.fail(err)
+		return
+	}
+	if c, err = d.skipSpace(); err != nil {
+		d.fail(err)
+		return
+	}
+	if key == "features" {
+		if c != '[' {
+			d.err = errFeaturesInvalid
+			return
+		}
+		d.hasFeatures = true
+		d.state, d.first = decodeFeatures, true
+		return
+	}
+	d.buf, err = d.readValue(d.buf[:0], c)
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	if !gjson.ValidBytes(d.buf) {
+		d.fail(nil)
+		return
+	}
+	if key == "type" {
+		rType := gjson.ParseBytes(d.buf)
+		if rType.Type != gjson.String {
+			d.err = errTypeInvalid
+		} else if rType.Str != "FeatureCollection" {
+			d.err = fmt.Errorf(fmtErrTypeIsUnknown, rType.Str)
+		}
+		d.hasType = true
+		return
+	}
+	if len(d.members) == 0 {
+		d.members = append(d.members, '{')
+	} else {
+		d.members = append(d.members, ',')
+	}
+	d.members = append(d.members, rawKey...)
+	d.members = append(d.members, ':')
+	d.members = append(d.members, pretty.Ugly(d.buf)...)
+}
+
+// nextFeature reads the next feature, or the end of the "features" array.
+// A feature that cannot be parsed returns an error, which does not stop the
+// decoder, because the rest of the stream can still be read.
+func (d *Decoder) nextFeature() (Object, error) {
+	c, err := d.skipSpace()
+	if err != nil {
+		d.fail(err)
+		return nil, nil
+	}
+	if c == ']' {
+		d.state, d.first = decodeMembers, false
+		return nil, nil
+	}
+	if !d.first {
+		if c != ',' {
+			d.failFeature(nil)
+			return nil, nil
+		}
+		if c, err = d.skipSpace(); err != nil {
+			d.failFeature(err)
+			return nil, nil
+		}
+	}
+	d.first = false
+	d.buf, err = d.readValue(d.buf[:0], c)
+	if err != nil {
+		d.failFeature(err)
+		return nil, nil
+	}
+	index := d.index
+	d.index++
+	obj, err := Parse(string(d.buf), d.opts)
+	if err != nil {
+		return nil, &DecodeError{Index: index, Err: err}
+	}
+	return obj, nil
+}
+
+// fail stops the decoder with a read error, or with an invalid data error
+// for a nil or io.EOF error, which means that the json is not valid.
+func (d *Decoder) fail(err error) {
+	if err == nil || err == io.EOF {
+		err = errDataInvalid
+	}
+	d.err = err
+}
+
+func (d *Decoder) failFeature(err error) {
+	d.fail(err)
+	d.err = &DecodeError{Index: d.index, Err: d.err}
+}
+
+// skipSpace returns the next byte that is not whitespace.
+func (d *Decoder) skipSpace() (byte, error) {
+	for {
+		c, err := d.rd.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return c, nil
+		}
+	}
+}
+
+// readValue appends the raw bytes of the json value that starts with c. The
+// value is not validated, other than matching its brackets and quotes.
+// Brackets that do not match return an error, because the end of the value
+// is not known.
+func (d *Decoder) readValue(dst []byte, c byte) ([]byte, error) {
+	dst = append(dst, c)
+	switch c {
+	case '"':
+		return d.readString(dst)
+	case '{', '[':
+		// the closing brackets that are expected
+		stack := []byte{c + 2}
+		for len(stack) > 0 {
+			c, err := d.rd.ReadByte()
+			if err != nil {
+				return dst, err
+			}
+			dst = append(dst, c)
+			switch c {
+			case '{', '[':
+				stack = append(stack, c+2)
+			case '}', ']':
+				if c != stack[len(stack)-1] {
+					return dst, errDataInvalid
+				}
+				stack = stack[:len(stack)-1]
+			case '"':
+				if dst, err = d.readString(dst); err != nil {
+					return dst, err
+				}
+			}
+		}
+		return dst, nil
+	case '}', ']', ',', ':':
+		return dst, errDataInvalid
+	default:
+		// numbers, true, false, and null
+		for {
+			c, err := d.rd.ReadByte()
+			if err == io.EOF {
+				return dst, nil
+			}
+			if err != nil {
+				return dst, err
+			}
+			switch c {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return dst, d.rd.UnreadByte()
+			}
+			dst = append(dst, c)
+		}
+	}
+}
+
+// readString appends the rest of a json string, including the closing quote.
+func (d *Decoder) readString(dst []byte) ([]byte, error) {
+	for {
+		c, err := d.rd.ReadByte()
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, c)
+		switch c {
+		case '\\':
+			c, err := d.rd.ReadByte()
+			if err != nil {
+				return dst, err
+			}
+			dst = append(dst, c)
+		case '"':
+			return dst, nil
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package geojson
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type errReader struct{ err error }
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func decodeAll(t *testing.T, d *Decoder) ([]Object, error) {
+	t.Helper()
+	var objs []Object
+	for {
+		obj, err := d.Next()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return objs, err
+		}
+		objs = append(objs, obj)
+	}
+}
+
+func TestDecoderBoston(t *testing.T) {
+	data, err := ioutil.ReadFile("test_files/boston_subset.geojson")
+	expect(t, err == nil)
+	c := parseCollection(t, string(data), false)
+	// read a byte at a time, which is the worst case for the buffering
+	d := NewDecoder(iotest.OneByteReader(strings.NewReader(string(data))), nil)
+	objs, err := decodeAll(t, d)
+	expect(t, err == nil)
+	expect(t, len(objs) == len(c.Children()))
+	for i, obj := range objs {
+		expect(t, obj.JSON() == c.Children()[i].JSON())
+	}
+	_, err = d.Next()
+	expect(t, err == io.EOF)
+}
+
+func TestDecoderMembers(t *testing.T) {
+	json := `{
+		"bbox": [ -112, 33, -111, 34 ],
+		"type": "FeatureCollection",
+		"name": "before \"features\"",
+		"features": [
+			{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[-112,33]},"properties":{}},
+			{"type":"Point","coordinates":[-111,34]}
+		],
+		"count": 2,
+		"empty": null
+	}`
+	d := NewDecoder(strings.NewReader(json), nil)
+	obj, err := d.Next()
+	expect(t, err == nil)
+	expect(t, obj.(*Feature).Members() == `{"id":1,"properties":{}}`)
+	expect(t, d.Members() ==
+		`{"bbox":[-112,33,-111,34],"name":"before \"features\""}`)
+	obj, err = d.Next()
+	expect(t, err == nil)
+	expect(t, obj.JSON() == `{"type":"Point","coordinates":[-111,34]}`)
+	_, err = d.Next()
+	expect(t, err == io.EOF)
+	expect(t, d.Members() == `{"bbox":[-112,33,-111,34],`+
+		`"name":"before \"features\"","count":2,"empty":null}`)
+
+	d = NewDecoder(strings.NewReader(
+		`{"type":"FeatureCollection","features":[]}`), nil)
+	objs, err := decodeAll(t, d)
+	expect(t, err == nil && len(objs) == 0 && d.Members() == "")
+	d = NewDecoder(strings.NewReader(
+		`{"features":[],"type":"FeatureCollection","a":true}`), nil)
+	_, err = decodeAll(t, d)
+	expect(t, err == nil && d.Members() == `{"a":true}`)
+}
+
+func TestDecoderOptions(t *testing.T) {
+	json := `{"type":"FeatureCollection","features":[
+		{"type":"Point","coordinates":[1,2]},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},
+		"properties":{"type":"Circle","radius":-1}}
+	]}`
+	opts := *DefaultParseOptions
+	opts.AllowSimplePoints = true
+	d := NewDecoder(strings.NewReader(json), &opts)
+	obj, err := d.Next()
+	expect(t, err == nil)
+	_, ok := obj.(*SimplePoint)
+	expect(t, ok)
+	d = NewDecoder(strings.NewReader(json), nil)
+	d.Next()
+	obj, err = d.Next()
+	expect(t, err == nil)
+	_, ok = obj.(*Circle)
+	expect(t, ok)
+
+	opts = *DefaultParseOptions
+	opts.RequireValid = true
+	d = NewDecoder(strings.NewReader(json), &opts)
+	_, err = d.Next()
+	expect(t, err == nil)
+	_, err = d.Next()
+	var derr *DecodeError
+	expect(t, errors.As(err, &derr))
+	expect(t, derr.Index == 1 && derr.Err == errCircleRadiusInvalid)
+	expect(t, err.Error() == "feature 1: invalid circle radius")
+	_, err = d.Next()
+	expect(t, err == io.EOF)
+}
+
+func TestDecoderErrors(t *testing.T) {
+	decodeErr := func(json string) error {
+		t.Helper()
+		_, err := decodeAll(t, NewDecoder(strings.NewReader(json), nil))
+		return err
+	}
+	expect(t, decodeErr(``) == errDataInvalid)
+	expect(t, decodeErr(`[]`) == errDataInvalid)
+	expect(t, decodeErr(`{"type":"FeatureCollection"`) == errDataInvalid)
+	expect(t, decodeErr(`{"type":"FeatureCollection"}`) == errFeaturesMissing)
+	expect(t, decodeErr(`{"features":[]}`) == errTypeMissing)
+	expect(t, decodeErr(`{"type":1,"features":[]}`) == errTypeInvalid)
+	expect(t, decodeErr(`{"type":"Feature","features":[]}`).Error() ==
+		"type 'Feature' is unknown")
+	expect(t, decodeErr(`{"type":"FeatureCollection","features":{}}`) ==
+		errFeaturesInvalid)
+	expect(t, decodeErr(`{"type":"FeatureCollection" "features":[]}`) ==
+		errDataInvalid)
+	expect(t, decodeErr(`{"type":"FeatureCollection","a":[1,}`) ==
+		errDataInvalid)
+
+	// the stream ends in the second feature
+	json := `{"type":"FeatureCollection","features":[
+		{"type":"Point","coordinates":[1,2]},
+		{"type":"Point","coordinates":[1,`
+	d := NewDecoder(strings.NewReader(json), nil)
+	objs, err := decodeAll(t, d)
+	expect(t, len(objs) == 1)
+	var derr *DecodeError
+	expect(t, errors.As(err, &derr) && derr.Index == 1)
+	expect(t, errors.Is(err, errDataInvalid))
+	_, err2 := d.Next()
+	expect(t, err2 == err)
+
+	// a feature that is not valid does not stop the decoder
+	json = `{"type":"FeatureCollection","features":[
+		{"type":"Point","coordinates":[1,2]},
+		{"type":"Point"},
+		{"type":"Point","coordinates":[1,2,]},
+		{"type":"Point","coordinates":[3,4]}
+	]}`
+	d = NewDecoder(strings.NewReader(json), nil)
+	_, err = d.Next()
+	expect(t, err == nil)
+	_, err = d.Next()
+	expect(t, errors.As(err, &derr) && derr.Index == 1 &&
+		derr.Err == errCoordinatesMissing)
+	_, err = d.Next()
+	expect(t, errors.As(err, &derr) && derr.Index == 2 &&
+		derr.Err == errDataInvalid)
+	obj, err := d.Next()
+	expect(t, err == nil && obj.Center() == P(3, 4))
+	_, err = d.Next()
+	expect(t, err == io.EOF)
+
+	// brackets that do not match stop the decoder
+	d = NewDecoder(strings.NewReader(`{"type":"FeatureCollection",`+
+		`"features":[{"type":"Point","coordinates":[1,2}]}`), nil)
+	_, err = d.Next()
+	expect(t, errors.As(err, &derr) && derr.Index == 0 &&
+		derr.Err == errDataInvalid)
+	_, err2 = d.Next()
+	expect(t, err2 == err)
+
+	// read errors are returned as they are
+	readErr := errors.New("read error")
+	d = NewDecoder(io.MultiReader(
+		strings.NewReader(`{"type":"FeatureCollection","features":[`),
+		errReader{readErr}), nil)
+	_, err = d.Next()
+	expect(t, err == readErr)
+}
@@ -0,0 +1,297 @@
+package geojson
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// The following functions return the minimum geodesic distance in meters
+// between geometries. Segments are treated as great-circle arcs and the
+// distance is zero when the geometries intersect. Empty geometries fall back
+// to the distance between the centers of their rectangles.
+
+func geoDistancePointSegment(point geometry.Point, seg geometry.Segment) float64 {
+	return geo.DistanceToSegment(point.Y, point.X, seg.A.Y, seg.A.X, seg.B.Y,
+		seg.B.X)
+}
+
+func geoDistanceSegments(a, b geometry.Segment) float64 {
+	if a.IntersectsSegment(b) {
+		return 0
+	}
+	// the nearest points of two arcs that do not cross include at least one
+	// of the end points.
+	return math.Min(
+		math.Min(geoDistancePointSegment(a.A, b), geoDistancePointSegment(a.B, b)),
+		math.Min(geoDistancePointSegment(b.A, a), geoDistancePointSegment(b.B, a)),
+	)
+}
+
+// geoRectExpand expands a rectangle by a distance in meters. The result
+// contains every point that is within the distance of the rectangle.
+func geoRectExpand(rect geometry.Rect, meters float64) geometry.Rect {
+	// longitude degrees are the widest at the latitude farthest from the
+	// equator.
+	lat := rect.Max.Y
+	if -rect.Min.Y > lat {
+		lat = rect.Min.Y
+	}
+	minLat, minLon, maxLat, maxLon := geo.RectFromCenter(lat, 0, meters)
+	dlon := (maxLon - minLon) / 2
+	var dlat float64
+	if lat >= 0 {
+		dlat = lat - minLat
+	} else {
+		dlat = maxLat - lat
+	}
+	return geometry.Rect{
+		Min: geometry.Point{X: rect.Min.X - dlon, Y: rect.Min.Y - dlat},
+		Max: geometry.Point{X: rect.Max.X + dlon, Y: rect.Max.Y + dlat},
+	}
+}
+
+// geoBulge returns how many degrees of latitude a great-circle arc, with its
+// end points in the rectangle, can reach past the rectangle toward a pole.
+// It's infinite when the arc can span 180 degrees of longitude or more.
+func geoBulge(rect geometry.Rect) float64 {
+	width := rect.Max.X - rect.Min.X
+	if !(width < 180) {
+		return math.Inf(1)
+	}
+	if width <= 0 {
+		return 0
+	}
+	// The vertex of the great circle, which is its point nearest to a pole,
+	// is at most half of the width from one of the end points, at latitude
+	// atan(tan(lat)/cos(width/2)), which is the farthest past the end
+	// point for end points at atan(sqrt(cos(width/2))).
+	c := math.Cos(width / 2 * math.Pi / 180)
+	lat := math.Max(math.Abs(rect.Min.Y), math.Abs(rect.Max.Y)) * math.Pi / 180
+	lat = math.Min(lat, math.Atan(math.Sqrt(c)))
+	return (math.Atan(math.Tan(lat)/c) - lat) * 180 / math.Pi
+}
+
+// geoArcRect returns the rectangle that contains the great-circle arcs that
+// have their end points in a rectangle, which bulge toward the poles.
+func geoArcRect(rect geometry.Rect) geometry.Rect {
+	bulge := geoBulge(rect)
+	rect.Min.Y = math.Max(rect.Min.Y-bulge, -90)
+	rect.Max.Y = math.Min(rect.Max.Y+bulge, 90)
+	return rect
+}
+
+// geoRectDistanceBound returns a lower bound of the distance in meters
+// between any point in one rectangle and any point in another, including
+// the arcs between their points. The bound is zero when the rectangles
+// intersect.
+func geoRectDistanceBound(a, b geometry.Rect) float64 {
+	a, b = geoArcRect(a), geoArcRect(b)
+	var dlat float64
+	if b.Min.Y > a.Max.Y {
+		dlat = b.Min.Y - a.Max.Y
+	} else if a.Min.Y > b.Max.Y {
+		dlat = a.Min.Y - b.Max.Y
+	}
+	// the longitude gap, either way around the antimeridian
+	var dlon float64
+	if b.Min.X > a.Max.X {
+		dlon = math.Min(b.Min.X-a.Max.X, a.Min.X+360-b.Max.X)
+	} else if a.Min.X > b.Max.X {
+		dlon = math.Min(a.Min.X-b.Max.X, b.Min.X+360-a.Max.X)
+	}
+	dlon = math.Min(math.Max(dlon, 0), 180)
+	// a longitude gap is the shortest at the latitude nearest to a pole
+	lat := math.Max(math.Max(math.Abs(a.Min.Y), math.Abs(a.Max.Y)),
+		math.Max(math.Abs(b.Min.Y), math.Abs(b.Max.Y)))
+	return math.Max(geo.DistanceTo(0, 0, dlat, 0),
+		geo.DistanceTo(lat, 0, lat, dlon))
+}
+
+// geoSearchNearest returns the smallest distance, as calculated by the dist
+// function, from the target to the series segments, or best when none are
+// closer. The segment index of the series is used to only visit the segments
+// near the target. The target is a point or the end points of a segment.
+func geoSearchNearest(
+	series geometry.Series, target geometry.Rect, best float64,
+	dist func(seg geometry.Segment) float64,
+) float64 {
+	visit := func(seg geometry.Segment, _ int) bool {
+		if d := dist(seg); d < best {
+			best = d
+		}
+		return best > 0
+	}
+	srect := series.Rect()
+	if series.Index() == nil ||
+		math.IsInf(geoBulge(srect), 1) || math.IsInf(geoBulge(target), 1) {
+		// the segments can span half of the world, which their rectangles
+		// in the index do not cover, so every segment is visited
+		n := series.NumSegments()
+		for i := 0; i < n; i++ {
+			if !visit(series.SegmentAt(i), i) {
+				break
+			}
+		}
+		return best
+	}
+	if math.IsInf(best, 1) {
+		// Grow a window around the target until a segment is found, which
+		// provides the upper bound for the final search.
+		win := math.Max(srect.Max.X-srect.Min.X, srect.Max.Y-srect.Min.Y) / 64
+		for math.IsInf(best, 1) {
+			wrect := geometry.Rect{
+				Min: geometry.Point{X: target.Min.X - win, Y: target.Min.Y - win},
+				Max: geometry.Point{X: target.Max.X + win, Y: target.Max.Y + win},
+			}
+			if win == 0 || wrect.ContainsRect(srect) {
+				wrect = srect
+			}
+			series.Search(wrect, visit)
+			if wrect == srect {
+				break
+			}
+			win *= 2
+		}
+	}
+	if best > 0 {
+		// The arcs of the target and of the segments bulge toward the poles,
+		// past the rectangles of their end points, and the segments that
+		// are near across the antimeridian are 360 degrees away.
+		rect := geoRectExpand(geoArcRect(target), best)
+		bulge := geoBulge(srect)
+		rect.Min.Y -= bulge
+		rect.Max.Y += bulge
+		series.Search(rect, visit)
+		if best > 0 && rect.Min.X < -180 {
+			series.Search(rect.Move(360, 0), visit)
+		}
+		if best > 0 && rect.Max.X > 180 {
+			series.Search(rect.Move(-360, 0), visit)
+		}
+	}
+	return best
+}
+
+func geoDistancePointSeries(point geometry.Point, series geometry.Series) float64 {
+	if series.NumSegments() == 0 {
+		if series.NumPoints() == 0 {
+			return math.Inf(1)
+		}
+		return geoDistancePoints(point, series.PointAt(0))
+	}
+	return geoSearchNearest(series, point.Rect(), math.Inf(1),
+		func(seg geometry.Segment) float64 {
+			return geoDistancePointSegment(point, seg)
+		},
+	)
+}
+
+// geoDistanceSeries returns the distance between the segments of two series,
+// best when none are closer.
+func geoDistanceSeries(a, b geometry.Series, best float64) float64 {
+	if a.NumSegments() > b.NumSegments() {
+		a, b = b, a
+	}
+	n := a.NumSegments()
+	if n == 0 {
+		if a.NumPoints() == 0 {
+			return best
+		}
+		return math.Min(best, geoDistancePointSeries(a.PointAt(0), b))
+	}
+	for i := 0; i < n && best > 0; i++ {
+		seg := a.SegmentAt(i)
+		best = geoSearchNearest(b, seg.Rect(), best,
+			func(other geometry.Segment) float64 {
+				return geoDistanceSegments(seg, other)
+			},
+		)
+	}
+	return best
+}
+
+// polyRings returns the exterior and holes of a polygon.
+func polyRings(poly *geometry.Poly) []geometry.Ring {
+	if poly == nil || poly.Exterior == nil {
+		return nil
+	}
+	rings := make([]geometry.Ring, 0, 1+len(poly.Holes))
+	rings = append(rings, poly.Exterior)
+	return append(rings, poly.Holes...)
+}
+
+// geoDistanceFallback returns the distance between the rectangle centers when
+// the distance could not be calculated, such as with empty geometries.
+func geoDistanceFallback(dist float64, a, b geometry.Rect) float64 {
+	if math.IsInf(dist, 1) {
+		return geoDistancePoints(a.Center(), b.Center())
+	}
+	return dist
+}
+
+func geoDistancePointLine(point geometry.Point, line *geometry.Line) float64 {
+	return geoDistanceFallback(geoDistancePointSeries(point, line),
+		point.Rect(), line.Rect())
+}
+
+func geoDistancePointPoly(point geometry.Point, poly *geometry.Poly) float64 {
+	if poly.ContainsPoint(point) {
+		return 0
+	}
+	dist := math.Inf(1)
+	for _, ring := range polyRings(poly) {
+		dist = math.Min(dist, geoDistancePointSeries(point, ring))
+	}
+	return geoDistanceFallback(dist, point.Rect(), poly.Rect())
+}
+
+func geoDistancePointRect(point geometry.Point, rect geometry.Rect) float64 {
+	return geoDistancePointPoly(point, &geometry.Poly{Exterior: rect})
+}
+
+func geoDistanceLineLine(a, b *geometry.Line) float64 {
+	if a.IntersectsLine(b) {
+		return 0
+	}
+	return geoDistanceFallback(geoDistanceSeries(a, b, math.Inf(1)),
+		a.Rect(), b.Rect())
+}
+
+func geoDistanceLinePoly(line *geometry.Line, poly *geometry.Poly) float64 {
+	if poly.IntersectsLine(line) {
+		return 0
+	}
+	dist := math.Inf(1)
+	for _, ring := range polyRings(poly) {
+		dist = geoDistanceSeries(line, ring, dist)
+	}
+	return geoDistanceFallback(dist, line.Rect(), poly.Rect())
+}
+
+func geoDistanceLineRect(line *geometry.Line, rect geometry.Rect) float64 {
+	return geoDistanceLinePoly(line, &geometry.Poly{Exterior: rect})
+}
+
+func geoDistancePolyPoly(a, b *geometry.Poly) float64 {
+	if a.IntersectsPoly(b) {
+		return 0
+	}
+	dist := math.Inf(1)
+	for _, ringA := range polyRings(a) {
+		for _, ringB := range polyRings(b) {
+			dist = geoDistanceSeries(ringA, ringB, dist)
+		}
+	}
+	return geoDistanceFallback(dist, a.Rect(), b.Rect())
+}
+
+func geoDistancePolyRect(poly *geometry.Poly, rect geometry.Rect) float64 {
+	return geoDistancePolyPoly(poly, &geometry.Poly{Exterior: rect})
+}
+
+func geoDistanceRectRect(a, b geometry.Rect) float64 {
+	return geoDistancePolyPoly(&geometry.Poly{Exterior: a},
+		&geometry.Poly{Exterior: b})
+}
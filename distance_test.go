@@ -0,0 +1,274 @@
+package geojson
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func expectDistance(t *testing.T, dist, expect float64) {
+	t.Helper()
+	if math.Abs(dist-expect) > 0.001 {
+		t.Fatalf("expected %f, got %f", expect, dist)
+	}
+}
+
+func TestDistancePoint(t *testing.T) {
+	poly := expectJSON(t, `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]]]}`, nil)
+	// inside and on the edge
+	expectDistance(t, PO(5, 5).Distance(poly), 0)
+	expectDistance(t, PO(10, 5).Distance(poly), 0)
+	expectDistance(t, poly.Distance(PO(5, 5)), 0)
+	// nearest to the edge, not the center
+	expectDistance(t, PO(5, -1).Distance(poly), geo.DistanceTo(-1, 5, 0, 5))
+	expectDistance(t, poly.Distance(PO(5, -1)), geo.DistanceTo(-1, 5, 0, 5))
+	expectDistance(t, PO(11, 11).Distance(poly), geo.DistanceTo(11, 11, 10, 10))
+	expectDistance(t, RO(0, 0, 10, 10).Distance(PO(5, -1)),
+		geo.DistanceTo(-1, 5, 0, 5))
+	expectDistance(t, PO(5, -1).Distance(RO(0, 0, 10, 10)),
+		geo.DistanceTo(-1, 5, 0, 5))
+	line := LO([]geometry.Point{{X: -50, Y: 0}, {X: 50, Y: 0}})
+	expectDistance(t, PO(0, 1).Distance(line), geo.DistanceTo(1, 0, 0, 0))
+	expectDistance(t, line.Distance(PO(0, 1)), geo.DistanceTo(1, 0, 0, 0))
+	expectDistance(t, line.Distance(PO(0, 0)), 0)
+	expectDistance(t, PO(1, 2).Distance(PO(1, 2)), 0)
+	expectDistance(t, NewSimplePoint(P(0, 1)).Distance(line),
+		geo.DistanceTo(1, 0, 0, 0))
+}
+
+func TestDistanceHoles(t *testing.T) {
+	poly := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,10],[0,10],[0,0]],
+		[[2,2],[8,2],[8,8],[2,8],[2,2]]
+	]}`, nil)
+	expectDistance(t, PO(5, 5).Distance(poly),
+		geo.DistanceToSegment(5, 5, 2, 8, 8, 8))
+	expectDistance(t, PO(1, 5).Distance(poly), 0)
+}
+
+func TestDistanceLines(t *testing.T) {
+	a := LO([]geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}})
+	b := LO([]geometry.Point{{X: 5, Y: 1}, {X: 5, Y: 10}})
+	c := LO([]geometry.Point{{X: 5, Y: -1}, {X: 5, Y: 10}})
+	expectDistance(t, a.Distance(b), geo.DistanceTo(1, 5, 0, 5))
+	expectDistance(t, b.Distance(a), geo.DistanceTo(1, 5, 0, 5))
+	expectDistance(t, a.Distance(c), 0)
+	poly := expectJSON(t, `{"type":"Polygon","coordinates":[[[0,2],[10,2],[10,10],[0,10],[0,2]]]}`, nil)
+	expectDistance(t, a.Distance(poly), geo.DistanceTo(0, 0, 2, 0))
+	expectDistance(t, poly.Distance(a), geo.DistanceTo(0, 0, 2, 0))
+	expectDistance(t, b.Distance(poly), 0)
+	expectDistance(t, a.Distance(RO(20, -5, 30, 5)), geo.DistanceTo(0, 10, 0, 20))
+	expectDistance(t, RO(20, -5, 30, 5).Distance(a), geo.DistanceTo(0, 10, 0, 20))
+}
+
+func TestDistancePolygons(t *testing.T) {
+	a := expectJSON(t, `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]]]}`, nil)
+	b := expectJSON(t, `{"type":"Polygon","coordinates":[[[12,0],[20,0],[20,10],[12,10],[12,0]]]}`, nil)
+	c := expectJSON(t, `{"type":"Polygon","coordinates":[[[2,2],[4,2],[4,4],[2,4],[2,2]]]}`, nil)
+	expectDistance(t, a.Distance(b),
+		geo.DistanceToSegment(10, 12, 0, 10, 10, 10))
+	expectDistance(t, b.Distance(a),
+		geo.DistanceToSegment(10, 12, 0, 10, 10, 10))
+	expectDistance(t, a.Distance(c), 0)
+	expectDistance(t, c.Distance(a), 0)
+	expectDistance(t, RO(0, 0, 10, 10).Distance(RO(12, 0, 20, 10)),
+		a.Distance(b))
+	expectDistance(t, RO(0, 0, 10, 10).Distance(RO(2, 2, 4, 4)), 0)
+	expectDistance(t, a.Distance(RO(12, 0, 20, 10)), a.Distance(b))
+}
+
+func TestDistanceCollections(t *testing.T) {
+	mp := expectJSON(t, `{"type":"MultiPoint","coordinates":[[0,0],[50,50]]}`, nil)
+	expectDistance(t, mp.Distance(PO(0, 1)), geo.DistanceTo(0, 0, 1, 0))
+	expectDistance(t, PO(0, 1).Distance(mp), geo.DistanceTo(0, 0, 1, 0))
+	expectDistance(t, mp.Distance(RO(49, 49, 51, 51)), 0)
+	expectDistance(t, RO(49, 49, 51, 51).Distance(mp), 0)
+	fc := expectJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[10,0],[10,10]]},"properties":{}}
+	]}`, nil)
+	expectDistance(t, fc.Distance(PO(11, 5)), geo.DistanceToSegment(5, 11, 0, 10, 10, 10))
+	expectDistance(t, PO(11, 5).Distance(fc), geo.DistanceToSegment(5, 11, 0, 10, 10, 10))
+}
+
+func TestDistanceCircle(t *testing.T) {
+	circle := NewCircle(P(0, 0), 1000, 64)
+	expectDistance(t, circle.Distance(PO(0, 0)), 0)
+	expectDistance(t, circle.Distance(PO(1, 0)), geo.DistanceTo(0, 0, 0, 1)-1000)
+	expectDistance(t, circle.Distance(RO(-1, -1, 1, 1)), 0)
+}
+
+func TestDistanceIndexed(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var points []geometry.Point
+	for i := 0; i < 500; i++ {
+		points = append(points, geometry.Point{
+			X: rng.Float64()*40 - 20, Y: rng.Float64()*40 - 20,
+		})
+	}
+	noindex := geometry.NewLine(points, &geometry.IndexOptions{Kind: geometry.None})
+	qtree := geometry.NewLine(points, &geometry.IndexOptions{Kind: geometry.QuadTree, MinPoints: 64})
+	rtree := geometry.NewLine(points, &geometry.IndexOptions{Kind: geometry.RTree, MinPoints: 64})
+	expect(t, noindex.Index() == nil)
+	expect(t, qtree.Index() != nil)
+	expect(t, rtree.Index() != nil)
+	for i := 0; i < 200; i++ {
+		point := geometry.Point{
+			X: rng.Float64()*100 - 50, Y: rng.Float64()*100 - 50,
+		}
+		expected := math.Inf(1)
+		for j := 0; j < noindex.NumSegments(); j++ {
+			expected = math.Min(expected,
+				geoDistancePointSegment(point, noindex.SegmentAt(j)))
+		}
+		expectDistance(t, geoDistancePointLine(point, noindex), expected)
+		expectDistance(t, geoDistancePointLine(point, qtree), expected)
+		expectDistance(t, geoDistancePointLine(point, rtree), expected)
+	}
+	other := geometry.NewLine([]geometry.Point{{X: 30, Y: 30}, {X: 40, Y: 25}},
+		nil)
+	expectDistance(t, geoDistanceLineLine(other, qtree),
+		geoDistanceLineLine(other, noindex))
+	expectDistance(t, geoDistanceLineLine(rtree, other),
+		geoDistanceLineLine(noindex, other))
+}
+
+func TestDistanceCollectionIndexed(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	var children []Object
+	for i := 0; i < 300; i++ {
+		// points all over the world, including near the poles and the
+		// antimeridian
+		children = append(children, PO(rng.Float64()*360-180,
+			rng.Float64()*180-90))
+	}
+	indexed := new(MultiPoint)
+	indexed.children = children
+	indexed.parseInitRectIndex(DefaultParseOptions)
+	plain := new(MultiPoint)
+	plain.children = children
+	plain.parseInitRectIndex(&ParseOptions{IndexChildren: 0})
+	expect(t, indexed.tree != nil && plain.tree == nil)
+	for i := 0; i < 200; i++ {
+		point := P(rng.Float64()*360-180, rng.Float64()*180-90)
+		expected := math.Inf(1)
+		for _, child := range children {
+			expected = math.Min(expected, child.Distance(NewPoint(point)))
+		}
+		expectDistance(t, indexed.Distance(NewPoint(point)), expected)
+		expectDistance(t, indexed.DistancePoint(point), expected)
+		expectDistance(t, plain.Distance(NewPoint(point)), expected)
+	}
+}
+
+func TestRectDistanceBound(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	randRect := func() geometry.Rect {
+		x, y := rng.Float64()*340-180, rng.Float64()*160-90
+		return geometry.Rect{
+			Min: geometry.Point{X: x, Y: y},
+			Max: geometry.Point{X: x + rng.Float64()*20, Y: y + rng.Float64()*20},
+		}
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := randRect(), randRect()
+		bound := geoRectDistanceBound(a, b)
+		pa := geometry.Point{
+			X: a.Min.X + rng.Float64()*(a.Max.X-a.Min.X),
+			Y: a.Min.Y + rng.Float64()*(a.Max.Y-a.Min.Y),
+		}
+		pb := geometry.Point{
+			X: b.Min.X + rng.Float64()*(b.Max.X-b.Min.X),
+			Y: b.Min.Y + rng.Float64()*(b.Max.Y-b.Min.Y),
+		}
+		expect(t, bound <= geoDistancePoints(pa, pb)+1e-6)
+	}
+	expect(t, geoRectDistanceBound(R(0, 0, 1, 1), R(0.5, 0.5, 2, 2)) == 0)
+	// the antimeridian is not a gap
+	expect(t, geoRectDistanceBound(R(179, 0, 180, 1), R(-180, 0, -179, 1)) == 0)
+}
+
+// indexedLine returns a line of the points, and more points from the last
+// point toward the end, so that the line has a segment index.
+func indexedLine(points []geometry.Point, end geometry.Point) *geometry.Line {
+	last := points[len(points)-1]
+	for i := 1; i <= 300; i++ {
+		t := float64(i) / 300
+		points = append(points, geometry.Point{
+			X: last.X + (end.X-last.X)*t, Y: last.Y + (end.Y-last.Y)*t,
+		})
+	}
+	return geometry.NewLine(points, &geometry.IndexOptions{
+		Kind: geometry.RTree, MinPoints: 64,
+	})
+}
+
+// bruteDistancePointLine returns the distance from a point to a line by
+// visiting every segment.
+func bruteDistancePointLine(point geometry.Point, line *geometry.Line,
+) float64 {
+	best := math.Inf(1)
+	for i := 0; i < line.NumSegments(); i++ {
+		best = math.Min(best, geoDistancePointSegment(point, line.SegmentAt(i)))
+	}
+	return best
+}
+
+func TestDistanceIndexedArcs(t *testing.T) {
+	// the segments that are across the antimeridian
+	line := indexedLine([]geometry.Point{{X: -179.99, Y: 0}},
+		geometry.Point{X: -179.99, Y: 10})
+	expect(t, line.Index() != nil)
+	point := P(179.99, 0.5)
+	expected := bruteDistancePointLine(point, line)
+	expect(t, expected < 2300)
+	expectDistance(t, geoDistancePointLine(point, line), expected)
+	// a search with a best distance from an earlier part
+	expectDistance(t, geoSearchNearest(line, point.Rect(), 100000,
+		func(seg geometry.Segment) float64 {
+			return geoDistancePointSegment(point, seg)
+		},
+	), expected)
+	other := geometry.NewLine([]geometry.Point{{X: 179.99, Y: 0.5},
+		{X: 179.98, Y: 0.6}}, nil)
+	expectDistance(t, geoDistanceLineLine(other, line), expected)
+
+	// the first segment bulges toward the pole, past its rectangle
+	line = indexedLine([]geometry.Point{{X: -60, Y: 70}, {X: 60, Y: 70},
+		{X: 0, Y: 77}}, geometry.Point{X: 0, Y: 77.5})
+	expect(t, line.Index() != nil)
+	point = P(0, 80)
+	expected = geoDistancePointSegment(point, line.SegmentAt(0))
+	expect(t, expected < 40000)
+	expectDistance(t, bruteDistancePointLine(point, line), expected)
+	expectDistance(t, geoDistancePointLine(point, line), expected)
+	expectDistance(t, PO(0, 80).Distance(NewLineString(line)), expected)
+}
+
+func TestBulge(t *testing.T) {
+	expect(t, geoBulge(R(0, 0, 0, 10)) == 0)
+	expect(t, math.IsInf(geoBulge(R(-90, 0, 90, 10)), 1))
+	// the arc from (-60,70) to (60,70) reaches past 78 degrees
+	expect(t, geoBulge(R(-60, 70, 60, 70)) > 8.2)
+	rng := rand.New(rand.NewSource(4))
+	for i := 0; i < 1000; i++ {
+		x, y := rng.Float64()*300-180, rng.Float64()*180-90
+		rect := geometry.Rect{
+			Min: geometry.Point{X: x, Y: y},
+			Max: geometry.Point{
+				X: x + rng.Float64()*60, Y: math.Min(y+rng.Float64()*20, 90),
+			},
+		}
+		// the latitudes of the arc between the corners are in the rectangle
+		arc := geoArcRect(rect)
+		lat, _ := geo.IntermediatePoint(rect.Min.Y, rect.Min.X, rect.Max.Y,
+			rect.Max.X, rng.Float64())
+		expect(t, lat >= arc.Min.Y-1e-9 && lat <= arc.Max.Y+1e-9)
+		lat, _ = geo.IntermediatePoint(rect.Max.Y, rect.Min.X, rect.Max.Y,
+			rect.Max.X, 0.5)
+		expect(t, lat >= arc.Min.Y-1e-9 && lat <= arc.Max.Y+1e-9)
+	}
+}
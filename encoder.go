@@ -0,0 +1,157 @@
+package geojson
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/pretty"
+	"github.com/tidwall/sjson"
+)
+
+var errEncoderClosed = errors.New("encoder closed")
+
+// encodeBufferSize is the size that the output is buffered to before it is
+// written.
+const encodeBufferSize = 32 * 1024
+
+// Encoder writes a GeoJSON FeatureCollection to a stream, one feature at a
+// time, without holding the whole collection in memory.
+type Encoder struct {
+	w       io.Writer
+	buf     []byte
+	count   int
+	rect    geometry.Rect
+	hasRect bool
+	members string
+	bbox    *geometry.Rect
+	closed  bool
+	err     error
+}
+
+// NewEncoder returns an encoder that writes a FeatureCollection to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetMembers sets the foreign members of the collection, such as "name". The
+// members must be a valid json object, such as `{"name":"parcels"}`, or an
+// empty string. The members are written by Close, after the features.
+func (e *Encoder) SetMembers(members string) error {
+	members = strings.TrimSpace(members)
+	if members == "" || members == "{}" {
+		e.members = ""
+		return nil
+	}
+	if !gjson.Valid(members) || !gjson.Parse(members).IsObject() {
+		return errDataInvalid
+	}
+	for _, key := range []string{"type", "features"} {
+		if gjson.Get(members, key).Exists() {
+			members, _ = sjson.Delete(members, key)
+		}
+	}
+	e.members = string(pretty.Ugly([]byte(members)))
+	if e.members == "{}" {
+		e.members = ""
+	}
+	return nil
+}
+
+// SetBBox sets the "bbox" member of the collection, which replaces a "bbox"
+// in the members. It is written by Close, after the features, which allows
+// for using the Rect of the encoded objects.
+func (e *Encoder) SetBBox(rect geometry.Rect) {
+	e.bbox = &rect
+}
+
+// Rect returns the rectangle surrounding all of the objects that have been
+// encoded.
+func (e *Encoder) Rect() geometry.Rect {
+	return e.rect
+}
+
+// Count returns the number of objects that have been encoded.
+func (e *Encoder) Count() int {
+	return e.count
+}
+
+// Encode writes an object, which is usually a Feature, to the "features" of
+// the collection.
+func (e *Encoder) Encode(obj Object) error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.closed {
+		return errEncoderClosed
+	}
+	if e.count == 0 {
+		e.buf = append(e.buf, `{"type":"FeatureCollection","features":[`...)
+	} else {
+		e.buf = append(e.buf, ',')
+	}
+	e.buf = obj.AppendJSON(e.buf)
+	if !obj.Empty() {
+		if e.hasRect {
+			e.rect = unionRects(e.rect, obj.Rect())
+		} else {
+			e.rect, e.hasRect = obj.Rect(), true
+		}
+	}
+	e.count++
+	if len(e.buf) >= encodeBufferSize {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered output to the stream.
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.buf) > 0 {
+		_, e.err = e.w.Write(e.buf)
+		e.buf = e.buf[:0]
+	}
+	return e.err
+}
+
+// Close finishes the collection with its members and bbox, and writes the
+// rest of the output. It does not close the stream.
+func (e *Encoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.count == 0 {
+		e.buf = append(e.buf, `{"type":"FeatureCollection","features":[`...)
+	}
+	e.buf = append(e.buf, ']')
+	members := e.members
+	if e.bbox != nil {
+		if members != "" && gjson.Get(members, "bbox").Exists() {
+			members, _ = sjson.Delete(members, "bbox")
+		}
+		e.buf = append(e.buf, `,"bbox":[`...)
+		e.buf = appendJSONFloat(e.buf, e.bbox.Min.X)
+		e.buf = append(e.buf, ',')
+		e.buf = appendJSONFloat(e.buf, e.bbox.Min.Y)
+		e.buf = append(e.buf, ',')
+		e.buf = appendJSONFloat(e.buf, e.bbox.Max.X)
+		e.buf = append(e.buf, ',')
+		e.buf = appendJSONFloat(e.buf, e.bbox.Max.Y)
+		e.buf = append(e.buf, ']')
+	}
+	if len(members) > 2 {
+		e.buf = append(e.buf, ',')
+		e.buf = append(e.buf, members[1:len(members)-1]...)
+	}
+	e.buf = append(e.buf, '}')
+	return e.Flush()
+}
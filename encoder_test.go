@@ -0,0 +1,88 @@
+package geojson
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+type errWriter struct {
+	n   int
+	err error
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	w.n++
+	return 0, w.err
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	expect(t, e.Encode(PO(1, 2)) == nil)
+	expect(t, e.Encode(NewFeature(PO(3, 4), `{"id":1}`)) == nil)
+	expect(t, buf.Len() == 0)
+	expect(t, e.SetMembers(`{"type":"x", "name": "points"}`) == nil)
+	e.SetBBox(e.Rect())
+	expect(t, e.Count() == 2)
+	expect(t, e.Close() == nil)
+	expectJSON(t, buf.String(), `{"type":"FeatureCollection","features":[`+
+		`{"type":"Point","coordinates":[1,2]},`+
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4]},`+
+		`"id":1,"properties":{}}],"bbox":[1,2,3,4],"name":"points"}`)
+	expect(t, e.Close() == nil)
+	expect(t, e.Encode(PO(1, 2)) == errEncoderClosed)
+
+	// empty
+	buf.Reset()
+	e = NewEncoder(&buf)
+	expect(t, e.Close() == nil)
+	expect(t, buf.String() == `{"type":"FeatureCollection","features":[]}`)
+
+	// the bbox replaces the members bbox
+	buf.Reset()
+	e = NewEncoder(&buf)
+	expect(t, e.SetMembers(`{"bbox":[0,0,0,0],"a":1}`) == nil)
+	expect(t, e.SetMembers(`[]`) == errDataInvalid)
+	e.SetBBox(R(1, 2, 3, 4))
+	expect(t, e.Close() == nil)
+	expect(t, buf.String() == `{"type":"FeatureCollection","features":[],`+
+		`"bbox":[1,2,3,4],"a":1}`)
+	buf.Reset()
+	e = NewEncoder(&buf)
+	expect(t, e.SetMembers(`{"features":[]}`) == nil)
+	expect(t, e.Close() == nil)
+	expect(t, buf.String() == `{"type":"FeatureCollection","features":[]}`)
+}
+
+func TestEncoderStream(t *testing.T) {
+	data, err := ioutil.ReadFile("test_files/boston_subset.geojson")
+	expect(t, err == nil)
+	c := parseCollection(t, string(data), false)
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	expect(t, e.SetMembers(c.(Object).Members()) == nil)
+	for _, child := range c.Children() {
+		expect(t, e.Encode(child) == nil)
+	}
+	// the output is written as the buffer fills
+	expect(t, buf.Len() > 0)
+	expect(t, e.Close() == nil)
+	expect(t, buf.String() == c.(Object).JSON())
+	expect(t, e.Rect() == c.(Object).Rect())
+
+	// write errors are kept
+	werr := errors.New("write error")
+	w := &errWriter{err: werr}
+	e = NewEncoder(w)
+	for _, child := range c.Children() {
+		if err = e.Encode(child); err != nil {
+			break
+		}
+	}
+	expect(t, err == werr)
+	expect(t, e.Encode(PO(1, 2)) == werr)
+	expect(t, e.Close() == werr)
+	expect(t, w.n == 1)
+}
@@ -86,6 +86,20 @@ func (g *Feature) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+// AppendWKT appends the feature geometry as well-known text.
+func (g *Feature) AppendWKT(dst []byte) []byte {
+	return g.base.AppendWKT(dst)
+}
+
+func (g *Feature) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+// AppendWKB appends the feature geometry as well-known binary.
+func (g *Feature) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return g.base.AppendWKB(dst, opts)
+}
+
 func (g *Feature) Spatial() Spatial {
 	return g
 }
@@ -158,16 +172,7 @@ func parseJSONFeature(keys *parseKeys, opts *ParseOptions) (Object, error) {
 			if !opts.DisableCircleType &&
 				gjson.Get(members, "properties.type").String() == "Circle" {
 				// Circle
-				radius := gjson.Get(members, "properties.radius").Float()
-				units := gjson.Get(members, "properties.radius_units").String()
-				switch units {
-				case "", "m":
-				case "km":
-					radius *= 1000
-				default:
-					return nil, errCircleRadiusUnitsInvalid
-				}
-				return NewCircle(point.base, radius, 64), nil
+				return parseCircle(point.base, members, opts)
 			}
 		}
 	}
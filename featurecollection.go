@@ -45,6 +45,22 @@ func (g *FeatureCollection) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+// AppendWKT appends the geometries of the features as a well-known text
+// GEOMETRYCOLLECTION.
+func (g *FeatureCollection) AppendWKT(dst []byte) []byte {
+	return appendWKTGeometries(dst, g.children)
+}
+
+func (g *FeatureCollection) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+// AppendWKB appends the geometries of the features as a well-known binary
+// GeometryCollection.
+func (g *FeatureCollection) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBGeometries(dst, opts, g.children)
+}
+
 func parseJSONFeatureCollection(
 	keys *parseKeys, opts *ParseOptions,
 ) (Object, error) {
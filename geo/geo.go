@@ -33,6 +33,10 @@ func NormalizeDistance(meters float64) float64 {
 }
 
 func DistanceToHaversine(meters float64) float64 {
+	if meters >= piR {
+		// every point is within half of the circumference
+		return 1
+	}
 	// convert the given distance to its haversine
 	sin := math.Sin(0.5 * meters / earthRadius)
 	return sin * sin
@@ -48,6 +52,102 @@ func DistanceTo(latA, lonA, latB, lonB float64) (meters float64) {
 	return DistanceFromHaversine(a)
 }
 
+// vector returns the unit vector of a point on the sphere.
+func vector(lat, lon float64) (x, y, z float64) {
+	φ := lat * radians
+	λ := lon * radians
+	sφ, cφ := math.Sincos(φ)
+	sλ, cλ := math.Sincos(λ)
+	return cφ * cλ, cφ * sλ, sφ
+}
+
+// DistanceToSegment returns the minimum distance in meters from a point to
+// the segment between points A and B, where the segment is the shortest
+// great-circle arc.
+func DistanceToSegment(lat, lon, latA, lonA, latB, lonB float64) (
+	meters float64,
+) {
+	px, py, pz := vector(lat, lon)
+	ax, ay, az := vector(latA, lonA)
+	bx, by, bz := vector(latB, lonB)
+	// normal of the great circle that passes through A and B
+	nx, ny, nz := ay*bz-az*by, az*bx-ax*bz, ax*by-ay*bx
+	nl := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if nl > 1e-12 {
+		nx, ny, nz = nx/nl, ny/nl, nz/nl
+		// project the point onto the great circle
+		pn := px*nx + py*ny + pz*nz
+		cx, cy, cz := px-pn*nx, py-pn*ny, pz-pn*nz
+		// the projection is on the arc when it's between A and B
+		if (ay*cz-az*cy)*nx+(az*cx-ax*cz)*ny+(ax*cy-ay*cx)*nz >= 0 &&
+			(cy*bz-cz*by)*nx+(cz*bx-cx*bz)*ny+(cx*by-cy*bx)*nz >= 0 {
+			cl := math.Sqrt(cx*cx + cy*cy + cz*cz)
+			return math.Atan2(math.Abs(pn), cl) * earthRadius
+		}
+	}
+	// nearest to one of the end points
+	return math.Min(
+		DistanceTo(lat, lon, latA, lonA),
+		DistanceTo(lat, lon, latB, lonB),
+	)
+}
+
+// NearestPointOnSegment returns the point on the segment between points A
+// and B that is nearest to a point, where the segment is the shortest
+// great-circle arc.
+func NearestPointOnSegment(lat, lon, latA, lonA, latB, lonB float64) (
+	nearLat, nearLon float64,
+) {
+	px, py, pz := vector(lat, lon)
+	ax, ay, az := vector(latA, lonA)
+	bx, by, bz := vector(latB, lonB)
+	// normal of the great circle that passes through A and B
+	nx, ny, nz := ay*bz-az*by, az*bx-ax*bz, ax*by-ay*bx
+	nl := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if nl > 1e-12 {
+		nx, ny, nz = nx/nl, ny/nl, nz/nl
+		// project the point onto the great circle
+		pn := px*nx + py*ny + pz*nz
+		cx, cy, cz := px-pn*nx, py-pn*ny, pz-pn*nz
+		// the projection is on the arc when it's between A and B
+		if cx*cx+cy*cy+cz*cz > 1e-24 &&
+			(ay*cz-az*cy)*nx+(az*cx-ax*cz)*ny+(ax*cy-ay*cx)*nz >= 0 &&
+			(cy*bz-cz*by)*nx+(cz*bx-cx*bz)*ny+(cx*by-cy*bx)*nz >= 0 {
+			return math.Atan2(cz, math.Hypot(cx, cy)) * degrees,
+				math.Atan2(cy, cx) * degrees
+		}
+	}
+	// nearest to one of the end points
+	if DistanceTo(lat, lon, latA, lonA) <= DistanceTo(lat, lon, latB, lonB) {
+		return latA, lonA
+	}
+	return latB, lonB
+}
+
+// IntermediatePoint returns the point at a fraction of the way from point A
+// to point B, along the shortest great-circle arc between them.
+func IntermediatePoint(latA, lonA, latB, lonB, fraction float64) (
+	lat, lon float64,
+) {
+	switch fraction {
+	case 0:
+		return latA, lonA
+	case 1:
+		return latB, lonB
+	}
+	ax, ay, az := vector(latA, lonA)
+	bx, by, bz := vector(latB, lonB)
+	δ := math.Acos(math.Max(-1, math.Min(1, ax*bx+ay*by+az*bz)))
+	if δ < 1e-12 {
+		return latA, lonA
+	}
+	a := math.Sin((1-fraction)*δ) / math.Sin(δ)
+	b := math.Sin(fraction*δ) / math.Sin(δ)
+	x, y, z := a*ax+b*bx, a*ay+b*by, a*az+b*bz
+	return math.Atan2(z, math.Hypot(x, y)) * degrees,
+		math.Atan2(y, x) * degrees
+}
+
 // DestinationPoint return the destination from a point based on a
 // distance and bearing.
 func DestinationPoint(lat, lon, meters, bearingDegrees float64) (
@@ -81,8 +181,9 @@ func BearingTo(latA, lonA, latB, lonB float64) float64 {
 	return math.Mod(θ*degrees+360, 360)
 }
 
-// RectFromCenter calculates the bounding box surrounding a circle.
-func RectFromCenter(lat, lon, meters float64) (
+// rectFromCenter calculates the bounding box surrounding a circle, where
+// the longitudes may extend beyond -180 and 180.
+func rectFromCenter(lat, lon, meters float64) (
 	minLat, minLon, maxLat, maxLon float64,
 ) {
 	// convert degrees to radians
@@ -138,28 +239,61 @@ func RectFromCenter(lat, lon, meters float64) (
 		maxLon = math.Pi
 	}
 
+	// convert radians to degrees
+	minLat *= degrees
+	minLon *= degrees
+	maxLat *= degrees
+	maxLon *= degrees
+	return
+}
+
+// RectFromCenter calculates the bounding box surrounding a circle.
+func RectFromCenter(lat, lon, meters float64) (
+	minLat, minLon, maxLat, maxLon float64,
+) {
+	minLat, minLon, maxLat, maxLon = rectFromCenter(lat, lon, meters)
+
 	/* adjust for WRAPAROUND
 
 	Creates a bounding box that wraps around the Earth like a belt, which
 	results in returning false positive candidates (candidates that are
 	farther away from the center than the distance of the search radius).
 
-	An alternative method, possibly to be implemented in the future, would be
-	to split the bounding box into two boxes. This would return fewer (or no)
-	false positives, but will require significant changes to the API's of
-	geoJSON and any of its dependents. */
-	if minLon < -math.Pi || maxLon > math.Pi {
-		minLon = -math.Pi
-		maxLon = math.Pi
+	Use RectsFromCenter to split the bounding box into two boxes instead,
+	which returns fewer (or no) false positives. */
+	if minLon < -180 || maxLon > 180 {
+		minLon = -180
+		maxLon = 180
 	}
-
-	// convert radians to degrees
-	minLat *= degrees
-	minLon *= degrees
-	maxLat *= degrees
-	maxLon *= degrees
 	return
+}
 
+// Rect is a bounding box in degrees.
+type Rect struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// RectsFromCenter calculates the bounding boxes surrounding a circle. Unlike
+// RectFromCenter, a circle that crosses the antimeridian is split into two
+// boxes, one on each side, rather than a belt around the Earth.
+func RectsFromCenter(lat, lon, meters float64) []Rect {
+	minLat, minLon, maxLat, maxLon := rectFromCenter(lat, lon, meters)
+	if maxLon-minLon >= 360 {
+		return []Rect{{minLat, -180, maxLat, 180}}
+	}
+	if minLon < -180 {
+		return []Rect{
+			{minLat, minLon + 360, maxLat, 180},
+			{minLat, -180, maxLat, maxLon},
+		}
+	}
+	if maxLon > 180 {
+		return []Rect{
+			{minLat, minLon, maxLat, 180},
+			{minLat, -180, maxLat, maxLon - 360},
+		}
+	}
+	return []Rect{{minLat, minLon, maxLat, maxLon}}
 }
 
 func DegsToSemi(degs float64) int32 {
@@ -229,6 +229,42 @@ func TestRectFromCenter(t *testing.T) {
 
 }
 
+func TestRectsFromCenter(t *testing.T) {
+	// no wraparound
+	rects := RectsFromCenter(33, -112, 10000)
+	minLat, minLon, maxLat, maxLon := RectFromCenter(33, -112, 10000)
+	if len(rects) != 1 || rects[0] != (Rect{minLat, minLon, maxLat, maxLon}) {
+		t.Fatalf("expected one rect, got %v", rects)
+	}
+	// crossing the antimeridian from the east and the west
+	for _, lon := range []float64{179.5, -179.5} {
+		rects = RectsFromCenter(-17, lon, 100000)
+		if len(rects) != 2 {
+			t.Fatalf("expected two rects, got %v", rects)
+		}
+		if rects[0].MaxLon != 180 || rects[1].MinLon != -180 {
+			t.Fatalf("expected split at antimeridian, got %v", rects)
+		}
+		width := rects[0].MaxLon - rects[0].MinLon + rects[1].MaxLon -
+			rects[1].MinLon
+		_, minLon, _, maxLon = rectFromCenter(-17, lon, 100000)
+		if math.Abs(width-(maxLon-minLon)) > 1e-9 {
+			t.Fatalf("expected width %f, got %f", maxLon-minLon, width)
+		}
+		for _, rect := range rects {
+			if rect.MinLat >= -17 || rect.MaxLat <= -17 {
+				t.Fatalf("invalid latitudes: %v", rect)
+			}
+		}
+	}
+	// enclosing a pole
+	rects = RectsFromCenter(89.5, 179.5, 100000)
+	if len(rects) != 1 || rects[0].MinLon != -180 || rects[0].MaxLon != 180 ||
+		rects[0].MaxLat != 90 {
+		t.Fatalf("expected one belt, got %v", rects)
+	}
+}
+
 func TestSemi(t *testing.T) {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	N := 10_000_000
@@ -274,3 +310,95 @@ func TestSemi(t *testing.T) {
 			avg*100, largest*100)
 	}
 }
+
+func TestDistanceToSegment(t *testing.T) {
+	deg := earthRadius * radians // meters in one degree of a great circle
+	// perpendicular to a segment on the equator
+	value := DistanceToSegment(1, 0, 0, -10, 0, 10)
+	if !feq(value, deg) {
+		t.Fatalf("expected '%v', got '%v'", deg, value)
+	}
+	// perpendicular to a segment on a meridian
+	value = DistanceToSegment(5, 2, 0, 0, 10, 0)
+	expect := math.Asin(math.Cos(5*radians)*math.Sin(2*radians)) * earthRadius
+	if !feq(value, expect) {
+		t.Fatalf("expected '%v', got '%v'", expect, value)
+	}
+	// beyond the end points
+	value = DistanceToSegment(0, 12, 0, -10, 0, 10)
+	if !feq(value, 2*deg) {
+		t.Fatalf("expected '%v', got '%v'", 2*deg, value)
+	}
+	value = DistanceToSegment(0, -13, 0, -10, 0, 10)
+	if !feq(value, 3*deg) {
+		t.Fatalf("expected '%v', got '%v'", 3*deg, value)
+	}
+	// on the segment
+	value = DistanceToSegment(0, 5, 0, -10, 0, 10)
+	if !feq(value, 0) {
+		t.Fatalf("expected '%v', got '%v'", 0, value)
+	}
+	// degenerate segment
+	value = DistanceToSegment(1, 0, 0, 0, 0, 0)
+	if !feq(value, deg) {
+		t.Fatalf("expected '%v', got '%v'", deg, value)
+	}
+	// never farther than the end points, and never negative
+	for i := 0; i < 10000; i++ {
+		lat, lon := rand.Float64()*180-90, rand.Float64()*360-180
+		latA, lonA := rand.Float64()*180-90, rand.Float64()*360-180
+		latB, lonB := rand.Float64()*180-90, rand.Float64()*360-180
+		value := DistanceToSegment(lat, lon, latA, lonA, latB, lonB)
+		max := math.Min(DistanceTo(lat, lon, latA, lonA),
+			DistanceTo(lat, lon, latB, lonB))
+		if value < 0 || value > max+1e-6 {
+			t.Fatalf("expected <= '%v', got '%v'", max, value)
+		}
+	}
+}
+
+func TestNearestPointOnSegment(t *testing.T) {
+	lat, lon := NearestPointOnSegment(1, 3, 0, -10, 0, 10)
+	if !feq(lat, 0) || !feq(lon, 3) {
+		t.Fatalf("expected '0 3', got '%v %v'", lat, lon)
+	}
+	lat, lon = NearestPointOnSegment(0, 12, 0, -10, 0, 10)
+	if lat != 0 || lon != 10 {
+		t.Fatalf("expected '0 10', got '%v %v'", lat, lon)
+	}
+	// the distance to the nearest point is the distance to the segment
+	for i := 0; i < 10000; i++ {
+		lat, lon := rand.Float64()*180-90, rand.Float64()*360-180
+		latA, lonA := rand.Float64()*180-90, rand.Float64()*360-180
+		latB, lonB := rand.Float64()*180-90, rand.Float64()*360-180
+		nlat, nlon := NearestPointOnSegment(lat, lon, latA, lonA, latB, lonB)
+		value := DistanceTo(lat, lon, nlat, nlon)
+		expect := DistanceToSegment(lat, lon, latA, lonA, latB, lonB)
+		if math.Abs(value-expect) > 1e-3 {
+			t.Fatalf("expected '%v', got '%v'", expect, value)
+		}
+	}
+}
+
+func TestIntermediatePoint(t *testing.T) {
+	lat, lon := IntermediatePoint(0, -10, 0, 10, 0.25)
+	if !feq(lat, 0) || !feq(lon, -5) {
+		t.Fatalf("expected '0 -5', got '%v %v'", lat, lon)
+	}
+	// the great circle between two points on a parallel goes poleward
+	lat, lon = IntermediatePoint(60, -10, 60, 10, 0.5)
+	if !(lat > 60) || !feq(lon, 0) {
+		t.Fatalf("expected '>60 0', got '%v %v'", lat, lon)
+	}
+	for i := 0; i < 1000; i++ {
+		latA, lonA := rand.Float64()*180-90, rand.Float64()*360-180
+		latB, lonB := rand.Float64()*180-90, rand.Float64()*360-180
+		f := rand.Float64()
+		lat, lon := IntermediatePoint(latA, lonA, latB, lonB, f)
+		d := DistanceTo(latA, lonA, latB, lonB)
+		if math.Abs(DistanceTo(latA, lonA, lat, lon)-f*d) > 1e-3 ||
+			math.Abs(DistanceTo(lat, lon, latB, lonB)-(1-f)*d) > 1e-3 {
+			t.Fatalf("not on the arc: %v %v", lat, lon)
+		}
+	}
+}
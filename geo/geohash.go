@@ -0,0 +1,207 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geo
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeohashMaxPrecision is the maximum number of characters of a geohash.
+const GeohashMaxPrecision = 12
+
+var geohashDecode [256]int8
+
+func init() {
+	for i := range geohashDecode {
+		geohashDecode[i] = -1
+	}
+	for i := 0; i < len(geohashBase32); i++ {
+		geohashDecode[geohashBase32[i]] = int8(i)
+	}
+}
+
+// geohashBits returns the number of longitude and latitude bits of a geohash
+// with the precision.
+func geohashBits(precision int) (lonBits, latBits uint) {
+	bits := uint(precision * 5)
+	return (bits + 1) / 2, bits / 2
+}
+
+// geohashCell returns the integer cell of the point at the precision.
+func geohashCell(point geometry.Point, precision int) (x, y uint64) {
+	lonBits, latBits := geohashBits(precision)
+	cell := func(v, min, max float64, bits uint) uint64 {
+		n := uint64(1) << bits
+		c := math.Floor((v - min) / (max - min) * float64(n))
+		if !(c >= 0) {
+			return 0
+		}
+		if c >= float64(n) {
+			return n - 1
+		}
+		return uint64(c)
+	}
+	return cell(point.X, -180, 180, lonBits), cell(point.Y, -90, 90, latBits)
+}
+
+// geohashString returns the geohash of the integer cell at the precision.
+func geohashString(x, y uint64, precision int) string {
+	lonBits, latBits := geohashBits(precision)
+	hash := make([]byte, precision)
+	var c, n byte
+	for i := 0; i < precision*5; i++ {
+		// even bits are longitude and odd bits are latitude
+		var bit uint64
+		if i%2 == 0 {
+			lonBits--
+			bit = x >> lonBits & 1
+		} else {
+			latBits--
+			bit = y >> latBits & 1
+		}
+		c = c<<1 | byte(bit)
+		if i%5 == 4 {
+			hash[n] = geohashBase32[c]
+			c, n = 0, n+1
+		}
+	}
+	return string(hash)
+}
+
+// geohashParse returns the integer cell of a geohash.
+func geohashParse(hash string) (x, y uint64, ok bool) {
+	if len(hash) == 0 || len(hash) > GeohashMaxPrecision {
+		return 0, 0, false
+	}
+	for i := 0; i < len(hash)*5; i++ {
+		c := geohashDecode[hash[i/5]]
+		if c < 0 {
+			return 0, 0, false
+		}
+		bit := uint64(c) >> uint(4-i%5) & 1
+		if i%2 == 0 {
+			x = x<<1 | bit
+		} else {
+			y = y<<1 | bit
+		}
+	}
+	return x, y, true
+}
+
+func geohashClamp(precision int) int {
+	if precision < 1 {
+		return 1
+	}
+	if precision > GeohashMaxPrecision {
+		return GeohashMaxPrecision
+	}
+	return precision
+}
+
+// GeohashEncode returns the geohash of the cell that contains the point,
+// where X is the longitude and Y is the latitude. The precision is the
+// number of characters, from 1 to 12.
+func GeohashEncode(point geometry.Point, precision int) string {
+	precision = geohashClamp(precision)
+	x, y := geohashCell(point, precision)
+	return geohashString(x, y, precision)
+}
+
+// GeohashEncodeRect returns the longest geohash, up to the max precision, of
+// a cell that contains the rectangle. It returns an empty string when no
+// cell contains the rectangle.
+func GeohashEncodeRect(rect geometry.Rect, maxPrecision int) string {
+	min := GeohashEncode(rect.Min, maxPrecision)
+	max := GeohashEncode(rect.Max, maxPrecision)
+	var n int
+	for n < len(min) && min[n] == max[n] {
+		n++
+	}
+	return min[:n]
+}
+
+// GeohashRect returns the rectangle of the cell of a geohash. It returns
+// false when the geohash is not valid.
+func GeohashRect(hash string) (geometry.Rect, bool) {
+	x, y, ok := geohashParse(hash)
+	if !ok {
+		return geometry.Rect{}, false
+	}
+	lonBits, latBits := geohashBits(len(hash))
+	lonSize := 360 / float64(uint64(1)<<lonBits)
+	latSize := 180 / float64(uint64(1)<<latBits)
+	return geometry.Rect{
+		Min: geometry.Point{
+			X: float64(x)*lonSize - 180,
+			Y: float64(y)*latSize - 90,
+		},
+		Max: geometry.Point{
+			X: float64(x+1)*lonSize - 180,
+			Y: float64(y+1)*latSize - 90,
+		},
+	}, true
+}
+
+// GeohashDecode returns the center of the cell of a geohash. It returns
+// false when the geohash is not valid.
+func GeohashDecode(hash string) (geometry.Point, bool) {
+	rect, ok := GeohashRect(hash)
+	if !ok {
+		return geometry.Point{}, false
+	}
+	return rect.Center(), true
+}
+
+// GeohashNeighbor returns the geohash of the cell that is dx cells east and
+// dy cells north of the cell of a geohash, with the same precision. The
+// longitude wraps around the antimeridian. It returns an empty string when
+// the geohash is not valid, or when the cell is past one of the poles.
+func GeohashNeighbor(hash string, dx, dy int) string {
+	x, y, ok := geohashParse(hash)
+	if !ok {
+		return ""
+	}
+	lonBits, latBits := geohashBits(len(hash))
+	ny := int64(y) + int64(dy)
+	if ny < 0 || ny >= int64(1)<<latBits {
+		return ""
+	}
+	nx := (int64(x) + int64(dx)) % (int64(1) << lonBits)
+	if nx < 0 {
+		nx += int64(1) << lonBits
+	}
+	return geohashString(uint64(nx), uint64(ny), len(hash))
+}
+
+// GeohashNeighbors returns the geohashes of the eight cells around the cell
+// of a geohash, in the order north, northeast, east, southeast, south,
+// southwest, west, and northwest. A neighbor past one of the poles is an
+// empty string.
+func GeohashNeighbors(hash string) [8]string {
+	return [8]string{
+		GeohashNeighbor(hash, 0, 1),
+		GeohashNeighbor(hash, 1, 1),
+		GeohashNeighbor(hash, 1, 0),
+		GeohashNeighbor(hash, 1, -1),
+		GeohashNeighbor(hash, 0, -1),
+		GeohashNeighbor(hash, -1, -1),
+		GeohashNeighbor(hash, -1, 0),
+		GeohashNeighbor(hash, -1, 1),
+	}
+}
+
+// GeohashChildren returns the geohashes of the 32 cells inside the cell of a
+// geohash, which are one character longer.
+func GeohashChildren(hash string) []string {
+	children := make([]string, len(geohashBase32))
+	for i := range children {
+		children[i] = hash + geohashBase32[i:i+1]
+	}
+	return children
+}
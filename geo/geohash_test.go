@@ -0,0 +1,94 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geo
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestGeohash(t *testing.T) {
+	point := geometry.Point{X: 10.40744, Y: 57.64911}
+	if hash := GeohashEncode(point, 11); hash != "u4pruydqqvj" {
+		t.Fatalf("expected '%v', got '%v'", "u4pruydqqvj", hash)
+	}
+	center, ok := GeohashDecode("ezs42")
+	if !ok || !feq(center.X, -5.60302734375) || !feq(center.Y, 42.60498046875) {
+		t.Fatalf("got %v", center)
+	}
+	rect, ok := GeohashRect("ezs42")
+	if !ok || !rect.ContainsPoint(center) ||
+		!feq(rect.Max.X-rect.Min.X, 360.0/(1<<13)) ||
+		!feq(rect.Max.Y-rect.Min.Y, 180.0/(1<<12)) {
+		t.Fatalf("got %v", rect)
+	}
+	for _, hash := range []string{"", "a", "ezs4i", "0123456789bcd"} {
+		if _, ok := GeohashRect(hash); ok {
+			t.Fatalf("expected '%v' to be invalid", hash)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		point := geometry.Point{
+			X: rand.Float64()*360 - 180,
+			Y: rand.Float64()*180 - 90,
+		}
+		precision := rand.Intn(GeohashMaxPrecision) + 1
+		hash := GeohashEncode(point, precision)
+		rect, ok := GeohashRect(hash)
+		if len(hash) != precision || !ok || !rect.ContainsPoint(point) {
+			t.Fatalf("%v %v %v", point, hash, rect)
+		}
+	}
+	if GeohashEncode(geometry.Point{X: 180, Y: 90}, 1) != "z" ||
+		GeohashEncode(geometry.Point{X: -180, Y: -90}, 1) != "0" {
+		t.Fatal("expected the corners to be encoded")
+	}
+	rect = geometry.Rect{
+		Min: geometry.Point{X: -5.6, Y: 42.6},
+		Max: geometry.Point{X: -5.59, Y: 42.61},
+	}
+	if hash := GeohashEncodeRect(rect, 12); hash != "ezs42" {
+		t.Fatalf("got '%v'", hash)
+	}
+	rect = geometry.Rect{
+		Min: geometry.Point{X: -1, Y: -1},
+		Max: geometry.Point{X: 1, Y: 1},
+	}
+	if hash := GeohashEncodeRect(rect, 12); hash != "" {
+		t.Fatalf("got '%v'", hash)
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	neighbors := GeohashNeighbors("ezs42")
+	expect := [8]string{
+		"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx",
+	}
+	if neighbors != expect {
+		t.Fatalf("expected %v, got %v", expect, neighbors)
+	}
+	// the longitude wraps around the antimeridian
+	if hash := GeohashNeighbor("xbp", 1, 0); hash != "800" {
+		t.Fatalf("got '%v'", hash)
+	}
+	if hash := GeohashNeighbor("80", -1, 0); hash != "xb" {
+		t.Fatalf("got '%v'", hash)
+	}
+	// nothing past the poles
+	neighbors = GeohashNeighbors("b")
+	if neighbors[0] != "" || neighbors[1] != "" || neighbors[7] != "" ||
+		neighbors[2] != "c" || neighbors[6] != "z" {
+		t.Fatalf("got %v", neighbors)
+	}
+	if GeohashNeighbor("a", 1, 0) != "" {
+		t.Fatal("expected an empty string for an invalid geohash")
+	}
+	children := GeohashChildren("ezs")
+	if len(children) != 32 || children[0] != "ezs0" || children[31] != "ezsz" {
+		t.Fatalf("got %v", children)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geo
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// RingArea returns the area of a ring on the sphere in square meters, where
+// X is the longitude and Y is the latitude. The area is the same for either
+// winding order. A nil ring has no area.
+func RingArea(ring geometry.Series) float64 {
+	if ring == nil {
+		return 0
+	}
+	n := ring.NumPoints()
+	if n < 3 {
+		return 0
+	}
+	// the sum of the areas between each edge and the south pole
+	var area float64
+	a := ring.PointAt(n - 1)
+	for i := 0; i < n; i++ {
+		b := ring.PointAt(i)
+		area += (b.X - a.X) * radians *
+			(2 + math.Sin(a.Y*radians) + math.Sin(b.Y*radians))
+		a = b
+	}
+	return math.Abs(area * earthRadius * earthRadius / 2)
+}
+
+// LineLength returns the length of a line on the sphere in meters, where X is
+// the longitude and Y is the latitude. A nil line has no length.
+func LineLength(line geometry.Series) float64 {
+	if line == nil {
+		return 0
+	}
+	var length float64
+	n := line.NumPoints()
+	for i := 1; i < n; i++ {
+		a, b := line.PointAt(i-1), line.PointAt(i)
+		length += DistanceTo(a.Y, a.X, b.Y, b.X)
+	}
+	return length
+}
+
+// RingLength returns the length of a ring on the sphere in meters, including
+// the edge from the last point to the first point when the ring is not
+// closed.
+func RingLength(ring geometry.Series) float64 {
+	if ring == nil {
+		return 0
+	}
+	length := LineLength(ring)
+	n := ring.NumPoints()
+	if n > 1 {
+		a, b := ring.PointAt(n-1), ring.PointAt(0)
+		length += DistanceTo(a.Y, a.X, b.Y, b.X)
+	}
+	return length
+}
+
+// RectArea returns the area on the sphere, in square meters, of the
+// rectangle between the latitudes and longitudes.
+func RectArea(minLat, minLon, maxLat, maxLon float64) float64 {
+	return math.Abs(earthRadius * earthRadius * (maxLon - minLon) * radians *
+		(math.Sin(maxLat*radians) - math.Sin(minLat*radians)))
+}
+
+// CapArea returns the area, in square meters, of a circle on the sphere with
+// a radius in meters.
+func CapArea(meters float64) float64 {
+	meters = math.Min(math.Max(meters, 0), piR)
+	return 2 * math.Pi * earthRadius * earthRadius *
+		(1 - math.Cos(meters/earthRadius))
+}
+
+// CapPerimeter returns the perimeter, in meters, of a circle on the sphere
+// with a radius in meters.
+func CapPerimeter(meters float64) float64 {
+	meters = math.Min(math.Max(meters, 0), piR)
+	return 2 * math.Pi * earthRadius * math.Sin(meters/earthRadius)
+}
+
+// RectPerimeter returns the perimeter on the sphere, in meters, of the
+// rectangle between the latitudes and longitudes, where the north and south
+// edges follow the latitudes.
+func RectPerimeter(minLat, minLon, maxLat, maxLon float64) float64 {
+	dLon := math.Abs(maxLon-minLon) * radians
+	dLat := math.Abs(maxLat-minLat) * radians
+	return earthRadius * (dLon*(math.Cos(minLat*radians)+
+		math.Cos(maxLat*radians)) + 2*dLat)
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= math.Abs(b)*tolerance
+}
+
+func TestRingArea(t *testing.T) {
+	ring := geometry.NewLine([]geometry.Point{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0},
+	}, nil)
+	area := RingArea(ring)
+	if !near(area, RectArea(0, 0, 1, 1), 1e-9) {
+		t.Fatalf("expected %v, got %v", RectArea(0, 0, 1, 1), area)
+	}
+	if !near(area, 1.2364e10, 1e-3) {
+		t.Fatalf("expected ~1.2364e10, got %v", area)
+	}
+	// the winding order does not change the area
+	rev := geometry.NewLine([]geometry.Point{
+		{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 0},
+	}, nil)
+	if !near(RingArea(rev), area, 1e-9) {
+		t.Fatalf("expected %v, got %v", area, RingArea(rev))
+	}
+	if RingArea(geometry.NewLine([]geometry.Point{{}, {X: 1}}, nil)) != 0 {
+		t.Fatal("expected zero")
+	}
+}
+
+func TestLineLength(t *testing.T) {
+	line := geometry.NewLine([]geometry.Point{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1},
+	}, nil)
+	expect := DistanceTo(0, 0, 0, 1) + DistanceTo(0, 1, 1, 1)
+	if !feq(LineLength(line), expect) {
+		t.Fatalf("expected %v, got %v", expect, LineLength(line))
+	}
+	expect += DistanceTo(1, 1, 0, 0)
+	if !feq(RingLength(line), expect) {
+		t.Fatalf("expected %v, got %v", expect, RingLength(line))
+	}
+	// the closing edge of a closed ring has no length
+	ring := geometry.NewLine([]geometry.Point{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0},
+	}, nil)
+	if !feq(RingLength(ring), expect) {
+		t.Fatalf("expected %v, got %v", expect, RingLength(ring))
+	}
+}
+
+func TestCapArea(t *testing.T) {
+	// a small cap is close to a flat circle
+	if !near(CapArea(1000), math.Pi*1000*1000, 1e-6) {
+		t.Fatalf("expected ~%v, got %v", math.Pi*1000*1000, CapArea(1000))
+	}
+	if !near(CapPerimeter(1000), 2*math.Pi*1000, 1e-6) {
+		t.Fatalf("expected ~%v, got %v", 2*math.Pi*1000, CapPerimeter(1000))
+	}
+	sphere := 4 * math.Pi * earthRadius * earthRadius
+	if !near(CapArea(piR*2), sphere, 1e-9) {
+		t.Fatalf("expected %v, got %v", sphere, CapArea(piR*2))
+	}
+	if CapArea(-1) != 0 || CapPerimeter(-1) != 0 {
+		t.Fatal("expected zero")
+	}
+}
+
+func TestRectPerimeter(t *testing.T) {
+	expect := DistanceTo(0, 0, 0, 1)*2 + DistanceTo(0, 0, 1, 0) +
+		DistanceTo(1, 0, 1, 1)
+	if !near(RectPerimeter(0, 0, 1, 1), expect, 1e-6) {
+		t.Fatalf("expected ~%v, got %v", expect, RectPerimeter(0, 0, 1, 1))
+	}
+}
@@ -0,0 +1,103 @@
+package geojson
+
+import (
+	"sort"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// GeohashCover returns a small set of geohashes, up to the max precision,
+// whose cells cover the object. A cell that is contained by the object is
+// not divided, and the cells that intersect the edges of the object are
+// divided until the max precision, or until dividing would go over the max
+// number of cells. A max cells of zero has no limit. The cells of precision
+// one that intersect the object are always returned, even when there are
+// more than the max cells. The cells that only touch the edges of the
+// object are not in the cover.
+func GeohashCover(obj Object, maxPrecision, maxCells int) []string {
+	if obj.Empty() {
+		return nil
+	}
+	if maxPrecision < 1 {
+		maxPrecision = 1
+	} else if maxPrecision > geo.GeohashMaxPrecision {
+		maxPrecision = geo.GeohashMaxPrecision
+	}
+	// classify returns the cells that are done, because they are contained
+	// by the object or are at the max precision, and the other cells that
+	// intersect the object.
+	classify := func(hashes []string, precision int) (done, more []string) {
+		for _, hash := range hashes {
+			rect, _ := geo.GeohashRect(hash)
+			in := func(point geometry.Point) bool {
+				return geo.GeohashEncode(point, precision) == hash
+			}
+			if !IntersectsCell(obj, rect, in) {
+				continue
+			}
+			cell := NewRect(rect)
+			if precision == maxPrecision || obj.Contains(cell) {
+				done = append(done, hash)
+			} else {
+				more = append(more, hash)
+			}
+		}
+		return done, more
+	}
+	cells, partial := classify(geo.GeohashChildren(""), 1)
+	for precision := 2; precision <= maxPrecision; precision++ {
+		var next []string
+		for i, hash := range partial {
+			done, more := classify(geo.GeohashChildren(hash), precision)
+			// the cells once this cell is divided, including the cells
+			// that are not divided yet
+			count := len(cells) + len(next) + len(partial) - i - 1 +
+				len(done) + len(more)
+			if maxCells > 0 && count > maxCells {
+				cells = append(cells, hash)
+				continue
+			}
+			cells = append(cells, done...)
+			next = append(next, more...)
+		}
+		partial = next
+	}
+	cells = append(cells, partial...)
+	return geohashCompact(cells)
+}
+
+// geohashCompact replaces the complete sets of 32 sibling cells with their
+// parent, and sorts the cells.
+func geohashCompact(cells []string) []string {
+	for {
+		sort.Strings(cells)
+		var compact []string
+		for i := 0; i < len(cells); i++ {
+			hash := cells[i]
+			if len(hash) > 1 && i+31 < len(cells) &&
+				geohashSiblings(cells[i:i+32]) {
+				compact = append(compact, hash[:len(hash)-1])
+				i += 31
+				continue
+			}
+			compact = append(compact, hash)
+		}
+		if len(compact) == len(cells) {
+			return cells
+		}
+		cells = compact
+	}
+}
+
+// geohashSiblings returns true when the 32 cells, which are sorted and
+// unique, are all of the children of one cell.
+func geohashSiblings(cells []string) bool {
+	parent := cells[0][:len(cells[0])-1]
+	for _, hash := range cells {
+		if len(hash) != len(parent)+1 || hash[:len(parent)] != parent {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package geojson
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// expectCover checks that the cells intersect the object, and that the
+// sample points of the object are in the cells.
+func expectCover(t *testing.T, obj Object, cells []string, maxCells int) {
+	t.Helper()
+	if maxCells > 0 && len(cells) > maxCells {
+		t.Fatalf("expected at most %d cells, got %d", maxCells, len(cells))
+	}
+	var rects []geometry.Rect
+	for _, hash := range cells {
+		rect, ok := geo.GeohashRect(hash)
+		expect(t, ok)
+		expect(t, obj.Intersects(NewRect(rect)))
+		rects = append(rects, rect)
+	}
+	covered := func(point geometry.Point) bool {
+		for _, rect := range rects {
+			if rect.ContainsPoint(point) {
+				return true
+			}
+		}
+		return false
+	}
+	rect := obj.Rect()
+	for i := 0; i < 1000; i++ {
+		point := geometry.Point{
+			X: rect.Min.X + rand.Float64()*(rect.Max.X-rect.Min.X),
+			Y: rect.Min.Y + rand.Float64()*(rect.Max.Y-rect.Min.Y),
+		}
+		if obj.Intersects(PO(point.X, point.Y)) && !covered(point) {
+			t.Fatalf("%v is not covered", point)
+		}
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	cells := GeohashCover(PO(10.40744, 57.64911), 7, 0)
+	expect(t, len(cells) == 1 && cells[0] == "u4pruyd")
+
+	poly := expectJSON(t, `{"type":"Polygon","coordinates":[[
+		[-71.1,42.3],[-71.0,42.3],[-71.0,42.4],[-71.05,42.35],[-71.1,42.4],
+		[-71.1,42.3]
+	]]}`, nil)
+	for _, maxCells := range []int{0, 8, 32, 100} {
+		cells := GeohashCover(poly, 6, maxCells)
+		expectCover(t, poly, cells, maxCells)
+	}
+	// more cells are more precise
+	area := func(cells []string) float64 {
+		var area float64
+		for _, hash := range cells {
+			rect, _ := geo.GeohashRect(hash)
+			area += (rect.Max.X - rect.Min.X) * (rect.Max.Y - rect.Min.Y)
+		}
+		return area
+	}
+	expect(t, area(GeohashCover(poly, 6, 100)) < area(GeohashCover(poly, 6, 8)))
+
+	// the cells that are contained by the rect are not divided
+	rect := RO(-10, 40, 0, 45)
+	cells = GeohashCover(rect, 4, 0)
+	expectCover(t, rect, cells, 0)
+	var divided int
+	for _, hash := range cells {
+		if len(hash) == 4 {
+			divided++
+		}
+	}
+	expect(t, divided < len(cells))
+
+	// all of the children are replaced by the parent
+	cell, _ := geo.GeohashRect("ezs42")
+	rect = RO(cell.Min.X+1e-9, cell.Min.Y+1e-9, cell.Max.X-1e-9,
+		cell.Max.Y-1e-9)
+	cells = GeohashCover(rect, 6, 0)
+	expect(t, len(cells) == 1 && cells[0] == "ezs42")
+
+	// across the antimeridian
+	mp := expectJSON(t, `{"type":"MultiPolygon","coordinates":[
+		[[[179.5,-1],[180,-1],[180,1],[179.5,1],[179.5,-1]]],
+		[[[-180,-1],[-179.5,-1],[-179.5,1],[-180,1],[-180,-1]]]
+	]}`, nil)
+	cells = GeohashCover(mp, 4, 64)
+	expectCover(t, mp, cells, 64)
+
+	expect(t, GeohashCover(NewGeometryCollection(nil), 4, 0) == nil)
+	// the cells of precision one are returned
+	cells = GeohashCover(RO(-1, -1, 1, 1), 4, 2)
+	expect(t, len(cells) == 4)
+}
+
+func TestGeohashCoverBoston(t *testing.T) {
+	data, err := ioutil.ReadFile("test_files/boston_subset.geojson")
+	expect(t, err == nil)
+	fc := parseCollection(t, string(data), false).(Object)
+	cells := GeohashCover(fc, 7, 64)
+	expectCover(t, fc, cells, 64)
+}
+
+func TestGeohashCoverTouching(t *testing.T) {
+	// the cells that only touch the edges or corners are not in the cover
+	unit := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[1,0],[1,1],[0,1],[0,0]]
+	]}`, nil)
+	cells := GeohashCover(unit, 4, 0)
+	expect(t, len(cells) > 0)
+	for _, hash := range cells {
+		expect(t, hash[:3] == "s00")
+	}
+	// a polygon that is aligned with the cell grid is one cell
+	rect, _ := geo.GeohashRect("s00")
+	aligned := NewPolygon(geometry.NewPoly([]geometry.Point{
+		rect.Min, {X: rect.Max.X, Y: rect.Min.Y}, rect.Max,
+		{X: rect.Min.X, Y: rect.Max.Y}, rect.Min,
+	}, nil, nil))
+	cells = GeohashCover(aligned, 5, 0)
+	expect(t, len(cells) == 1 && cells[0] == "s00")
+	cells = GeohashCover(NewRect(rect), 5, 0)
+	expect(t, len(cells) == 1 && cells[0] == "s00")
+	// a point on the edges of cells is in one cell
+	cells = GeohashCover(PO(0, 0), 3, 0)
+	expect(t, len(cells) == 1 && cells[0] == "s00")
+	cells = GeohashCover(PO(180, 90), 2, 0)
+	expect(t, len(cells) == 1 && cells[0] == "zz")
+	// a line along the edge between cells is in the cells on one side
+	line := expectJSON(t, `{"type":"LineString","coordinates":[
+		[0,0.2],[0,1]
+	]}`, nil)
+	cells = GeohashCover(line, 3, 0)
+	expect(t, len(cells) == 1 && cells[0] == "s00")
+}
+
+func TestGeohashCoverNearEdges(t *testing.T) {
+	// the objects that are very near to the edge of a cell are in the cell
+	p := P(44.99999, 10)
+	for _, precision := range []int{1, 2, 3, 5} {
+		hash := geo.GeohashEncode(p, precision)
+		cells := GeohashCover(NewPoint(p), precision, 0)
+		expect(t, len(cells) == 1 && cells[0] == hash)
+		cells = GeohashCover(NewSimplePoint(p), precision, 0)
+		expect(t, len(cells) == 1 && cells[0] == hash)
+	}
+	line := expectJSON(t, `{"type":"LineString","coordinates":[
+		[44.99999,10],[44.99999,11]
+	]}`, nil)
+	cells := GeohashCover(line, 1, 0)
+	expect(t, len(cells) == 1 && cells[0] == "s")
+	cells = GeohashCover(line, 3, 0)
+	expect(t, len(cells) > 0)
+	for _, hash := range cells {
+		rect, _ := geo.GeohashRect(hash)
+		expect(t, rect.Min.X < 44.99999 && rect.Max.X == 45)
+	}
+	thin := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[44.99999,10],[45,10],[45,11],[44.99999,11],[44.99999,10]]
+	]}`, nil)
+	cells = GeohashCover(thin, 1, 0)
+	expect(t, len(cells) == 1 && cells[0] == "s")
+	cells = GeohashCover(thin, 3, 0)
+	expect(t, len(cells) > 0)
+	for _, hash := range cells {
+		rect, _ := geo.GeohashRect(hash)
+		expect(t, rect.Max.X == 45)
+	}
+	// the same objects just past the edge are in the next cells
+	cells = GeohashCover(PO(45.00001, 10), 1, 0)
+	expect(t, len(cells) == 1 && cells[0] == "t")
+	// a circle that reaches just past the edge
+	circle := NewCircle(P(44, 10), geo.DistanceTo(10, 44, 10, 45)+1, 64)
+	cells = GeohashCover(circle, 1, 0)
+	expect(t, len(cells) == 2 && cells[0] == "s" && cells[1] == "t")
+}
@@ -0,0 +1,166 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// seriesCentroid returns the length-weighted centroid of the segments of a
+// series, and the length. The centroid of a series without length is the
+// mean of its points.
+func seriesCentroid(series Series) (Point, float64) {
+	var cx, cy, length float64
+	n := series.NumSegments()
+	for i := 0; i < n; i++ {
+		seg := series.SegmentAt(i)
+		d := math.Hypot(seg.B.X-seg.A.X, seg.B.Y-seg.A.Y)
+		cx += (seg.A.X + seg.B.X) / 2 * d
+		cy += (seg.A.Y + seg.B.Y) / 2 * d
+		length += d
+	}
+	if length > 0 {
+		return Point{X: cx / length, Y: cy / length}, length
+	}
+	n = series.NumPoints()
+	if n == 0 {
+		return Point{}, 0
+	}
+	for i := 0; i < n; i++ {
+		point := series.PointAt(i)
+		cx += point.X
+		cy += point.Y
+	}
+	return Point{X: cx / float64(n), Y: cy / float64(n)}, 0
+}
+
+// ringCentroid returns the area-weighted centroid of a ring, and the area,
+// which is positive for either winding order.
+func ringCentroid(ring Ring) (Point, float64) {
+	n := ring.NumSegments()
+	if n == 0 {
+		return Point{}, 0
+	}
+	// relative to the first point, which keeps the precision of rings that
+	// are far from the origin
+	o := ring.PointAt(0)
+	var cx, cy, area float64
+	for i := 0; i < n; i++ {
+		seg := ring.SegmentAt(i)
+		ax, ay := seg.A.X-o.X, seg.A.Y-o.Y
+		bx, by := seg.B.X-o.X, seg.B.Y-o.Y
+		cross := ax*by - bx*ay
+		cx += (ax + bx) * cross
+		cy += (ay + by) * cross
+		area += cross
+	}
+	if area == 0 {
+		return o, 0
+	}
+	return Point{X: o.X + cx/(3*area), Y: o.Y + cy/(3*area)},
+		math.Abs(area / 2)
+}
+
+// Length returns the planar length of the line.
+func (line *Line) Length() float64 {
+	_, length := seriesCentroid(line)
+	return length
+}
+
+// Centroid returns the planar length-weighted centroid of the line. The
+// centroid of a line without length is the mean of its points.
+func (line *Line) Centroid() Point {
+	centroid, _ := seriesCentroid(line)
+	return centroid
+}
+
+// Area returns the planar area of the polygon, with the area of the holes
+// subtracted.
+func (poly *Poly) Area() float64 {
+	if poly.Empty() {
+		return 0
+	}
+	_, area := ringCentroid(poly.Exterior)
+	for _, hole := range poly.Holes {
+		_, holeArea := ringCentroid(hole)
+		area -= holeArea
+	}
+	return math.Max(area, 0)
+}
+
+// Centroid returns the planar area-weighted centroid of the polygon, with
+// the holes subtracted. The centroid of a polygon without area is the
+// centroid of its exterior as a line. The centroid of a concave polygon may
+// be outside of the polygon, see PointOnSurface.
+func (poly *Poly) Centroid() Point {
+	if poly.Empty() {
+		return Point{}
+	}
+	centroid, area := ringCentroid(poly.Exterior)
+	cx, cy := centroid.X*area, centroid.Y*area
+	for _, hole := range poly.Holes {
+		holeCentroid, holeArea := ringCentroid(hole)
+		cx -= holeCentroid.X * holeArea
+		cy -= holeCentroid.Y * holeArea
+		area -= holeArea
+	}
+	if area <= 0 {
+		centroid, _ = seriesCentroid(poly.Exterior)
+		return centroid
+	}
+	return Point{X: cx / area, Y: cy / area}
+}
+
+// PointOnSurface returns a point that is inside of the polygon. It's the
+// middle of the widest span of the polygon along a horizontal line near the
+// middle of the polygon. A polygon without area returns its first point.
+func (poly *Poly) PointOnSurface() Point {
+	if poly.Empty() {
+		return Point{}
+	}
+	rings := append([]Ring{poly.Exterior}, poly.Holes...)
+	// the scan line is halfway between the two nearest vertex latitudes
+	// around the middle, which keeps it from crossing any vertex
+	rect := poly.Rect()
+	mid := (rect.Min.Y + rect.Max.Y) / 2
+	lo, hi := rect.Min.Y, rect.Max.Y
+	for _, ring := range rings {
+		for i := 0; i < ring.NumPoints(); i++ {
+			y := ring.PointAt(i).Y
+			if y <= mid && y > lo {
+				lo = y
+			}
+			if y > mid && y < hi {
+				hi = y
+			}
+		}
+	}
+	y := (lo + hi) / 2
+	var xs []float64
+	for _, ring := range rings {
+		n := ring.NumSegments()
+		for i := 0; i < n; i++ {
+			seg := ring.SegmentAt(i)
+			if (seg.A.Y > y) != (seg.B.Y > y) {
+				xs = append(xs, seg.A.X+(y-seg.A.Y)*
+					(seg.B.X-seg.A.X)/(seg.B.Y-seg.A.Y))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	var best Point
+	width := -1.0
+	for i := 0; i+1 < len(xs); i += 2 {
+		if xs[i+1]-xs[i] > width {
+			width = xs[i+1] - xs[i]
+			best = Point{X: (xs[i] + xs[i+1]) / 2, Y: y}
+		}
+	}
+	if width <= 0 {
+		return poly.Exterior.PointAt(0)
+	}
+	return best
+}
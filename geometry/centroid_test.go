@@ -0,0 +1,49 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import "testing"
+
+func TestLineCentroid(t *testing.T) {
+	line := NewLine([]Point{{0, 0}, {10, 0}, {10, 2}}, nil)
+	expect(t, line.Length() == 12)
+	expect(t, line.Centroid() == Point{X: 70.0 / 12, Y: 2.0 / 12})
+	line = NewLine([]Point{{1, 1}, {1, 1}}, nil)
+	expect(t, line.Length() == 0)
+	expect(t, line.Centroid() == Point{1, 1})
+}
+
+func TestPolyCentroid(t *testing.T) {
+	// an L shape, with the corner of its bounding rectangle outside
+	lshape := NewPoly([]Point{
+		{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 10}, {0, 10}, {0, 0},
+	}, nil, nil)
+	expect(t, lshape.Area() == 36)
+	c := lshape.Centroid()
+	expect(t, c.X > 3.22 && c.X < 3.23 && c.Y > 3.22 && c.Y < 3.23)
+	expect(t, !lshape.ContainsPoint(lshape.Rect().Center()))
+	expect(t, lshape.ContainsPoint(lshape.PointOnSurface()))
+
+	// a hole moves the centroid away
+	square := NewPoly([]Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		[][]Point{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}, nil)
+	expect(t, square.Area() == 12)
+	c = square.Centroid()
+	expect(t, c.X > 2 && c.Y > 2)
+	expect(t, square.ContainsPoint(square.PointOnSurface()))
+
+	// a C shape, with the centroid in the opening
+	cshape := NewPoly([]Point{
+		{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 8}, {10, 8}, {10, 10},
+		{0, 10}, {0, 0},
+	}, nil, nil)
+	expect(t, !cshape.ContainsPoint(cshape.Centroid()))
+	expect(t, cshape.ContainsPoint(cshape.PointOnSurface()))
+
+	flat := NewPoly([]Point{{0, 0}, {4, 0}, {0, 0}}, nil, nil)
+	expect(t, flat.Area() == 0)
+	expect(t, flat.Centroid() == Point{2, 0})
+	expect(t, flat.PointOnSurface() == Point{0, 0})
+}
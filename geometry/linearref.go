@@ -0,0 +1,148 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import "math"
+
+// lineDistances returns the planar distance along the line of each point.
+func lineDistances(line *Line) []float64 {
+	n := line.NumPoints()
+	dists := make([]float64, n)
+	for i := 1; i < n; i++ {
+		a, b := line.PointAt(i-1), line.PointAt(i)
+		dists[i] = dists[i-1] + math.Hypot(b.X-a.X, b.Y-a.Y)
+	}
+	return dists
+}
+
+// LinePosition returns the index of the segment, and the position from 0 to
+// 1 in the segment, at a distance along a line, where dists are the
+// increasing distances along the line of each point, in any units. The
+// distance is clamped to the line.
+func LinePosition(dists []float64, distance float64) (int, float64) {
+	n := len(dists)
+	if n < 2 || !(distance > 0) {
+		return 0, 0
+	}
+	if distance >= dists[n-1] {
+		return n - 2, 1
+	}
+	// the first segment that ends past the distance
+	i, j := 0, n-2
+	for i < j {
+		h := (i + j) / 2
+		if dists[h+1] <= distance {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	length := dists[i+1] - dists[i]
+	if length == 0 {
+		return i, 0
+	}
+	return i, (distance - dists[i]) / length
+}
+
+// lerp returns the point that is t of the way from a to b.
+func lerp(a, b Point, t float64) Point {
+	return Point{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+}
+
+// Interpolate returns the point at a fraction of the planar length of the
+// line, from 0 at the first point to 1 at the last point. The point is
+// interpolated linearly between the coordinates of its segment.
+func (line *Line) Interpolate(fraction float64) Point {
+	dists := lineDistances(line)
+	if len(dists) == 0 {
+		return Point{}
+	}
+	return line.InterpolateDistance(fraction * dists[len(dists)-1])
+}
+
+// InterpolateDistance returns the point at a planar distance along the
+// line. The distance is clamped to the length of the line.
+func (line *Line) InterpolateDistance(distance float64) Point {
+	dists := lineDistances(line)
+	switch len(dists) {
+	case 0:
+		return Point{}
+	case 1:
+		return line.PointAt(0)
+	}
+	i, t := LinePosition(dists, distance)
+	return lerp(line.PointAt(i), line.PointAt(i+1), t)
+}
+
+// Locate returns the fraction of the length of the line, and the planar
+// distance along the line, of the point on the line that is nearest to a
+// point.
+func (line *Line) Locate(point Point) (fraction, distance float64) {
+	dists := lineDistances(line)
+	n := len(dists)
+	if n < 2 {
+		return 0, 0
+	}
+	best := math.Inf(1)
+	for i := 0; i < n-1; i++ {
+		a, b := line.PointAt(i), line.PointAt(i+1)
+		dx, dy := b.X-a.X, b.Y-a.Y
+		var t float64
+		if dx != 0 || dy != 0 {
+			t = ((point.X-a.X)*dx + (point.Y-a.Y)*dy) / (dx*dx + dy*dy)
+			t = math.Max(0, math.Min(1, t))
+		}
+		near := lerp(a, b, t)
+		if d := math.Hypot(point.X-near.X, point.Y-near.Y); d < best {
+			best = d
+			distance = dists[i] + (dists[i+1]-dists[i])*t
+		}
+	}
+	if dists[n-1] == 0 {
+		return 0, 0
+	}
+	return distance / dists[n-1], distance
+}
+
+// Substring returns the part of the line between two fractions of its
+// length. The part is reversed when the start is after the end.
+func (line *Line) Substring(start, end float64) *Line {
+	dists := lineDistances(line)
+	if len(dists) == 0 {
+		return NewLine(nil, nil)
+	}
+	length := dists[len(dists)-1]
+	return line.SubstringDistance(start*length, end*length)
+}
+
+// SubstringDistance returns the part of the line between two planar
+// distances along the line. The part is reversed when the start is after
+// the end.
+func (line *Line) SubstringDistance(start, end float64) *Line {
+	dists := lineDistances(line)
+	if len(dists) < 2 {
+		return NewLine(seriesCopyPoints(line), nil)
+	}
+	reverse := start > end
+	if reverse {
+		start, end = end, start
+	}
+	i, ti := LinePosition(dists, start)
+	j, tj := LinePosition(dists, end)
+	points := []Point{lerp(line.PointAt(i), line.PointAt(i+1), ti)}
+	for k := i + 1; k <= j; k++ {
+		points = append(points, line.PointAt(k))
+	}
+	last := lerp(line.PointAt(j), line.PointAt(j+1), tj)
+	if last != points[len(points)-1] || len(points) == 1 {
+		points = append(points, last)
+	}
+	if reverse {
+		for a, b := 0, len(points)-1; a < b; a, b = a+1, b-1 {
+			points[a], points[b] = points[b], points[a]
+		}
+	}
+	return NewLine(points, nil)
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import "testing"
+
+func TestLineInterpolate(t *testing.T) {
+	line := NewLine([]Point{{0, 0}, {10, 0}, {10, 10}}, nil)
+	expect(t, line.Interpolate(0) == Point{0, 0})
+	expect(t, line.Interpolate(0.25) == Point{5, 0})
+	expect(t, line.Interpolate(0.75) == Point{10, 5})
+	expect(t, line.Interpolate(1) == Point{10, 10})
+	expect(t, line.Interpolate(2) == Point{10, 10})
+	expect(t, line.InterpolateDistance(-1) == Point{0, 0})
+	expect(t, line.InterpolateDistance(10) == Point{10, 0})
+	expect(t, NewLine(nil, nil).Interpolate(0.5) == Point{})
+	expect(t, NewLine([]Point{{1, 2}}, nil).Interpolate(0.5) == Point{1, 2})
+}
+
+func TestLineLocate(t *testing.T) {
+	line := NewLine([]Point{{0, 0}, {10, 0}, {10, 10}}, nil)
+	fraction, distance := line.Locate(Point{4, -3})
+	expect(t, fraction == 0.2 && distance == 4)
+	fraction, distance = line.Locate(Point{12, 5})
+	expect(t, fraction == 0.75 && distance == 15)
+	fraction, distance = line.Locate(Point{-5, -5})
+	expect(t, fraction == 0 && distance == 0)
+	fraction, distance = line.Locate(Point{20, 20})
+	expect(t, fraction == 1 && distance == 20)
+}
+
+func TestLineSubstring(t *testing.T) {
+	line := NewLine([]Point{{0, 0}, {10, 0}, {10, 10}}, nil)
+	sub := line.Substring(0.25, 0.75)
+	expect(t, sub.NumPoints() == 3)
+	expect(t, sub.PointAt(0) == Point{5, 0})
+	expect(t, sub.PointAt(1) == Point{10, 0})
+	expect(t, sub.PointAt(2) == Point{10, 5})
+	sub = line.SubstringDistance(15, 5)
+	expect(t, sub.NumPoints() == 3)
+	expect(t, sub.PointAt(0) == Point{10, 5})
+	expect(t, sub.PointAt(2) == Point{5, 0})
+	sub = line.SubstringDistance(2, 4)
+	expect(t, sub.NumPoints() == 2)
+	expect(t, sub.PointAt(0) == Point{2, 0} && sub.PointAt(1) == Point{4, 0})
+	sub = line.Substring(0, 1)
+	expect(t, sub.NumPoints() == 3 && sub.PointAt(2) == Point{10, 10})
+	sub = line.Substring(0.5, 0.5)
+	expect(t, sub.NumPoints() == 2 && sub.PointAt(0) == sub.PointAt(1))
+}
+
+func TestLinePosition(t *testing.T) {
+	dists := []float64{0, 10, 10, 30}
+	i, pos := LinePosition(dists, 5)
+	expect(t, i == 0 && pos == 0.5)
+	i, pos = LinePosition(dists, 10)
+	expect(t, i == 2 && pos == 0)
+	i, pos = LinePosition(dists, 25)
+	expect(t, i == 2 && pos == 0.75)
+	i, pos = LinePosition(dists, 40)
+	expect(t, i == 2 && pos == 1)
+	i, pos = LinePosition(dists, -1)
+	expect(t, i == 0 && pos == 0)
+	i, pos = LinePosition([]float64{0}, 1)
+	expect(t, i == 0 && pos == 0)
+}
@@ -0,0 +1,425 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// OverlayOp is the kind of overlay operation.
+type OverlayOp byte
+
+// OverlayOp types
+const (
+	OverlayIntersection OverlayOp = iota
+	OverlayUnion
+	OverlayDifference
+	OverlaySymDifference
+)
+
+func (op OverlayOp) String() string {
+	switch op {
+	default:
+		return "Unknown"
+	case OverlayIntersection:
+		return "Intersection"
+	case OverlayUnion:
+		return "Union"
+	case OverlayDifference:
+		return "Difference"
+	case OverlaySymDifference:
+		return "SymDifference"
+	}
+}
+
+// Intersection returns the area that is covered by both polygons.
+func (poly *Poly) Intersection(other *Poly) []*Poly {
+	return Overlay([]*Poly{poly}, []*Poly{other}, OverlayIntersection, nil)
+}
+
+// Union returns the area that is covered by either polygon.
+func (poly *Poly) Union(other *Poly) []*Poly {
+	return Overlay([]*Poly{poly}, []*Poly{other}, OverlayUnion, nil)
+}
+
+// Difference returns the area of the polygon that is not covered by the
+// other polygon.
+func (poly *Poly) Difference(other *Poly) []*Poly {
+	return Overlay([]*Poly{poly}, []*Poly{other}, OverlayDifference, nil)
+}
+
+// SymDifference returns the area that is covered by exactly one of the
+// polygons.
+func (poly *Poly) SymDifference(other *Poly) []*Poly {
+	return Overlay([]*Poly{poly}, []*Poly{other}, OverlaySymDifference, nil)
+}
+
+// Overlay performs an overlay operation on two sets of polygons, such as the
+// polygons of two multipolygons. The polygons in each set must not overlap
+// each other. The resulting polygons have counter-clockwise exteriors and
+// clockwise holes, and their rings are indexed using the provided options.
+func Overlay(a, b []*Poly, op OverlayOp, opts *IndexOptions) []*Poly {
+	a, b = overlayNonEmpty(a), overlayNonEmpty(b)
+	aedges := overlaySplit(a, b, true)
+	bedges := overlaySplit(b, a, false)
+	bset := make(map[Segment]bool, len(bedges))
+	for _, edge := range bedges {
+		bset[edge] = true
+	}
+	aset := make(map[Segment]bool, len(aedges))
+	for _, edge := range aedges {
+		aset[edge] = true
+	}
+	reverse := func(edge Segment) Segment {
+		return Segment{A: edge.B, B: edge.A}
+	}
+	var edges []Segment
+	for _, edge := range aedges {
+		if bset[edge] {
+			// shared boundary with both areas on the same side
+			if op == OverlayIntersection || op == OverlayUnion {
+				edges = append(edges, edge)
+			}
+			continue
+		}
+		if bset[reverse(edge)] {
+			// shared boundary with the areas on opposite sides
+			if op == OverlayDifference {
+				edges = append(edges, edge)
+			}
+			continue
+		}
+		inside := overlayContainsPoint(b, overlayMidpoint(edge))
+		switch op {
+		case OverlayIntersection:
+			if inside {
+				edges = append(edges, edge)
+			}
+		case OverlayUnion, OverlayDifference:
+			if !inside {
+				edges = append(edges, edge)
+			}
+		case OverlaySymDifference:
+			if inside {
+				edges = append(edges, reverse(edge))
+			} else {
+				edges = append(edges, edge)
+			}
+		}
+	}
+	for _, edge := range bedges {
+		if aset[edge] || aset[reverse(edge)] {
+			// shared boundaries are decided by the first set
+			continue
+		}
+		inside := overlayContainsPoint(a, overlayMidpoint(edge))
+		switch op {
+		case OverlayIntersection:
+			if inside {
+				edges = append(edges, edge)
+			}
+		case OverlayUnion:
+			if !inside {
+				edges = append(edges, edge)
+			}
+		case OverlayDifference:
+			if inside {
+				edges = append(edges, reverse(edge))
+			}
+		case OverlaySymDifference:
+			if inside {
+				edges = append(edges, reverse(edge))
+			} else {
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return overlayPolys(overlayRings(edges), opts)
+}
+
+func overlayNonEmpty(polys []*Poly) []*Poly {
+	var nonEmpty []*Poly
+	for _, poly := range polys {
+		if !poly.Empty() {
+			nonEmpty = append(nonEmpty, poly)
+		}
+	}
+	return nonEmpty
+}
+
+func overlayMidpoint(seg Segment) Point {
+	return Point{X: (seg.A.X + seg.B.X) / 2, Y: (seg.A.Y + seg.B.Y) / 2}
+}
+
+func overlayContainsPoint(polys []*Poly, point Point) bool {
+	for _, poly := range polys {
+		if poly.ContainsPoint(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlayEpsilon is the distance, relative to the size of the coordinates,
+// that a point can be from a segment and still be on the segment. Points
+// that were calculated by an earlier overlay are rarely exactly on the
+// segments that they came from.
+const overlayEpsilon = 1e-10
+
+// overlayOnSegment returns true if the point is on the segment.
+func overlayOnSegment(seg Segment, point Point) bool {
+	if point == seg.A || point == seg.B {
+		return true
+	}
+	scale := math.Max(math.Max(math.Abs(seg.A.X), math.Abs(seg.A.Y)),
+		math.Max(math.Abs(seg.B.X), math.Abs(seg.B.Y)))
+	eps := overlayEpsilon * math.Max(scale, 1)
+	rect := seg.Rect()
+	if point.X < rect.Min.X-eps || point.X > rect.Max.X+eps ||
+		point.Y < rect.Min.Y-eps || point.Y > rect.Max.Y+eps {
+		return false
+	}
+	length := math.Hypot(seg.B.X-seg.A.X, seg.B.Y-seg.A.Y)
+	return math.Abs(Orient(seg.A, seg.B, point)) <= eps*length
+}
+
+// overlayIntersections appends the points where the segment of the first set
+// meets the segment of the second set. The segments are always passed in the
+// same order, which guarantees that both are split at the same points.
+func overlayIntersections(dst []Point, a, b Segment) []Point {
+	n := len(dst)
+	for _, point := range [...]Point{b.A, b.B} {
+		if overlayOnSegment(a, point) {
+			dst = append(dst, point)
+		}
+	}
+	for _, point := range [...]Point{a.A, a.B} {
+		if overlayOnSegment(b, point) {
+			dst = append(dst, point)
+		}
+	}
+	if len(dst) > n {
+		return dst
+	}
+	d1 := Orient(a.A, a.B, b.A)
+	d2 := Orient(a.A, a.B, b.B)
+	d3 := Orient(b.A, b.B, a.A)
+	d4 := Orient(b.A, b.B, a.B)
+	if ((d1 < 0 && d2 > 0) || (d1 > 0 && d2 < 0)) &&
+		((d3 < 0 && d4 > 0) || (d3 > 0 && d4 < 0)) {
+		t := d3 / (d3 - d4)
+		dst = append(dst, Point{
+			X: a.A.X + t*(a.B.X-a.A.X),
+			Y: a.A.Y + t*(a.B.Y-a.A.Y),
+		})
+	}
+	return dst
+}
+
+// overlaySplit returns the ring edges of the polygons split at every point
+// where they meet the edges of the other polygons. The edges are directed so
+// that the area of the polygon is on the left.
+func overlaySplit(polys, others []*Poly, first bool) []Segment {
+	var edges []Segment
+	var points []Point
+	for _, poly := range polys {
+		rings := append([]Ring{poly.Exterior}, poly.Holes...)
+		for i, ring := range rings {
+			// exteriors are counter-clockwise and holes are clockwise
+			flip := ring.Clockwise() != (i > 0)
+			n := ring.NumSegments()
+			for j := 0; j < n; j++ {
+				seg := ring.SegmentAt(j)
+				if seg.A == seg.B {
+					continue
+				}
+				points = points[:0]
+				for _, other := range others {
+					for _, oring := range append([]Ring{other.Exterior},
+						other.Holes...) {
+						oring.Search(seg.Rect(),
+							func(oseg Segment, _ int) bool {
+								if first {
+									points = overlayIntersections(points,
+										seg, oseg)
+								} else {
+									points = overlayIntersections(points,
+										oseg, seg)
+								}
+								return true
+							},
+						)
+					}
+				}
+				edges = overlayAppendPieces(edges, seg, points, flip)
+			}
+		}
+	}
+	return edges
+}
+
+// overlayAppendPieces appends the pieces of a segment that is split at the
+// provided points.
+func overlayAppendPieces(dst []Segment, seg Segment, points []Point,
+	flip bool,
+) []Segment {
+	dx, dy := seg.B.X-seg.A.X, seg.B.Y-seg.A.Y
+	param := func(point Point) float64 {
+		return (point.X-seg.A.X)*dx + (point.Y-seg.A.Y)*dy
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return param(points[i]) < param(points[j])
+	})
+	appendPiece := func(a, b Point) {
+		if flip {
+			a, b = b, a
+		}
+		dst = append(dst, Segment{A: a, B: b})
+	}
+	prev, end := seg.A, param(seg.B)
+	for _, point := range points {
+		if t := param(point); point == prev || t <= 0 || t >= end {
+			continue
+		}
+		appendPiece(prev, point)
+		prev = point
+	}
+	appendPiece(prev, seg.B)
+	return dst
+}
+
+// overlayRings chains the edges into closed rings. When more than one edge
+// leaves a point, the one with the sharpest left turn is taken, which keeps
+// rings that only touch at a point apart. A walk that comes back to one of
+// its own points closes a ring at that point, which keeps the rings when a
+// nearly degenerate edge leaves the walk with a dead end.
+func overlayRings(edges []Segment) [][]Point {
+	outgoing := make(map[Point][]int)
+	for i, edge := range edges {
+		outgoing[edge.A] = append(outgoing[edge.A], i)
+	}
+	used := make([]bool, len(edges))
+	var rings [][]Point
+	appendRing := func(ring []Point) {
+		ring = overlayClean(ring)
+		if len(ring) >= 4 {
+			rings = append(rings, ring)
+		}
+	}
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+		ring := []Point{edges[i].A}
+		visited := map[Point]int{edges[i].A: 0}
+		for cur := i; cur != -1; {
+			used[cur] = true
+			edge := edges[cur]
+			if j, ok := visited[edge.B]; ok {
+				loop := append(append([]Point{}, ring[j:]...), edge.B)
+				appendRing(loop)
+				for _, point := range ring[j+1:] {
+					delete(visited, point)
+				}
+				ring = ring[:j+1]
+				if j == 0 {
+					break
+				}
+			} else {
+				visited[edge.B] = len(ring)
+				ring = append(ring, edge.B)
+			}
+			dx, dy := edge.B.X-edge.A.X, edge.B.Y-edge.A.Y
+			next, best := -1, math.Inf(-1)
+			for _, j := range outgoing[edge.B] {
+				if used[j] {
+					continue
+				}
+				ex, ey := edges[j].B.X-edges[j].A.X, edges[j].B.Y-edges[j].A.Y
+				turn := math.Atan2(dx*ey-dy*ex, dx*ex+dy*ey)
+				if turn > best {
+					next, best = j, turn
+				}
+			}
+			cur = next
+		}
+	}
+	return rings
+}
+
+// overlayClean removes the points of a closed ring that are on a straight
+// line between their neighbors.
+func overlayClean(ring []Point) []Point {
+	points := ring[:len(ring)-1]
+	for changed := true; changed && len(points) >= 3; {
+		changed = false
+		n := len(points)
+		for i := 0; i < n; i++ {
+			prev, next := points[(i+n-1)%n], points[(i+1)%n]
+			if Orient(prev, points[i], next) == 0 {
+				points = append(points[:i], points[i+1:]...)
+				changed = true
+				break
+			}
+		}
+	}
+	if len(points) < 3 {
+		return nil
+	}
+	return append(points, points[0])
+}
+
+func overlayArea(ring []Point) float64 {
+	var area float64
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i].X*ring[i+1].Y - ring[i+1].X*ring[i].Y
+	}
+	return area / 2
+}
+
+// overlayPolys turns the rings into polygons, where each clockwise ring
+// becomes a hole of the smallest counter-clockwise ring that contains it.
+func overlayPolys(rings [][]Point, opts *IndexOptions) []*Poly {
+	type exterior struct {
+		ring  Ring
+		area  float64
+		holes [][]Point
+	}
+	var exteriors []*exterior
+	var holes [][]Point
+	for _, ring := range rings {
+		area := overlayArea(ring)
+		if area > 0 {
+			exteriors = append(exteriors, &exterior{
+				ring: newRing(ring, opts), area: area,
+			})
+		} else if area < 0 {
+			holes = append(holes, ring)
+		}
+	}
+	for _, hole := range holes {
+		point := overlayMidpoint(Segment{A: hole[0], B: hole[1]})
+		var owner *exterior
+		for _, ext := range exteriors {
+			if (owner == nil || ext.area < owner.area) &&
+				ringContainsPoint(ext.ring, point, false).hit {
+				owner = ext
+			}
+		}
+		if owner != nil {
+			owner.holes = append(owner.holes, hole)
+		}
+	}
+	polys := make([]*Poly, 0, len(exteriors))
+	for _, ext := range exteriors {
+		poly := &Poly{Exterior: ext.ring}
+		for _, hole := range ext.holes {
+			poly.Holes = append(poly.Holes, newRing(hole, opts))
+		}
+		polys = append(polys, poly)
+	}
+	return polys
+}
@@ -0,0 +1,193 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func overlayTestArea(polys []*Poly) float64 {
+	var area float64
+	for _, poly := range polys {
+		area += math.Abs(overlayArea(seriesCopyPoints(poly.Exterior)))
+		for _, hole := range poly.Holes {
+			area -= math.Abs(overlayArea(seriesCopyPoints(hole)))
+		}
+	}
+	return area
+}
+
+func overlayTestSquare(minX, minY, maxX, maxY float64) []Point {
+	return []Point{
+		{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY},
+	}
+}
+
+func expectOverlay(t *testing.T, polys []*Poly, count int, area float64) {
+	t.Helper()
+	if len(polys) != count {
+		t.Fatalf("expected %d polygons, got %d", count, len(polys))
+	}
+	if got := overlayTestArea(polys); math.Abs(got-area) > 1e-9 {
+		t.Fatalf("expected area %f, got %f", area, got)
+	}
+	for _, poly := range polys {
+		expect(t, !poly.Exterior.Clockwise())
+		for _, hole := range poly.Holes {
+			expect(t, hole.Clockwise())
+		}
+	}
+}
+
+func TestOverlayOverlapping(t *testing.T) {
+	a := NewPoly(overlayTestSquare(0, 0, 10, 10), nil, nil)
+	b := NewPoly(overlayTestSquare(5, 5, 15, 15), nil, nil)
+	expectOverlay(t, a.Intersection(b), 1, 25)
+	expectOverlay(t, a.Union(b), 1, 175)
+	expectOverlay(t, a.Difference(b), 1, 75)
+	expectOverlay(t, b.Difference(a), 1, 75)
+	expectOverlay(t, a.SymDifference(b), 2, 150)
+	union := a.Union(b)[0]
+	expect(t, union.Exterior.NumPoints() == 9)
+	expect(t, union.ContainsPoint(P(12, 12)))
+	expect(t, !union.ContainsPoint(P(12, 2)))
+}
+
+func TestOverlayDisjoint(t *testing.T) {
+	a := NewPoly(overlayTestSquare(0, 0, 10, 10), nil, nil)
+	b := NewPoly(overlayTestSquare(20, 0, 30, 10), nil, nil)
+	expectOverlay(t, a.Intersection(b), 0, 0)
+	expectOverlay(t, a.Union(b), 2, 200)
+	expectOverlay(t, a.Difference(b), 1, 100)
+	expectOverlay(t, a.SymDifference(b), 2, 200)
+}
+
+func TestOverlayContained(t *testing.T) {
+	a := NewPoly(overlayTestSquare(0, 0, 10, 10), nil, nil)
+	b := NewPoly(overlayTestSquare(2, 2, 8, 8), nil, nil)
+	expectOverlay(t, a.Intersection(b), 1, 36)
+	expectOverlay(t, a.Union(b), 1, 100)
+	diff := a.Difference(b)
+	expectOverlay(t, diff, 1, 64)
+	expect(t, len(diff[0].Holes) == 1)
+	expect(t, !diff[0].ContainsPoint(P(5, 5)))
+	expectOverlay(t, b.Difference(a), 0, 0)
+	expectOverlay(t, a.SymDifference(b), 1, 64)
+}
+
+func TestOverlaySharedEdges(t *testing.T) {
+	a := NewPoly(overlayTestSquare(0, 0, 10, 10), nil, nil)
+	b := NewPoly(overlayTestSquare(10, 0, 20, 10), nil, nil)
+	union := a.Union(b)
+	expectOverlay(t, union, 1, 200)
+	expect(t, union[0].Exterior.NumPoints() == 5)
+	expectOverlay(t, a.Intersection(b), 0, 0)
+	expectOverlay(t, a.Difference(b), 1, 100)
+	// identical
+	expectOverlay(t, a.Intersection(a), 1, 100)
+	expectOverlay(t, a.Union(a), 1, 100)
+	expectOverlay(t, a.Difference(a), 0, 0)
+	expectOverlay(t, a.SymDifference(a), 0, 0)
+	// partially shared and clockwise input
+	c := NewPoly([]Point{{5, 0}, {15, 0}, {15, -10}, {5, -10}, {5, 0}}, nil, nil)
+	expect(t, c.Exterior.Clockwise())
+	expectOverlay(t, a.Union(c), 1, 200)
+	expectOverlay(t, a.Difference(c), 1, 100)
+}
+
+func TestOverlayHoles(t *testing.T) {
+	a := NewPoly(overlayTestSquare(0, 0, 10, 10),
+		[][]Point{overlayTestSquare(2, 2, 8, 8)}, nil)
+	b := NewPoly(overlayTestSquare(4, -5, 6, 15), nil, nil)
+	expectOverlay(t, a.Intersection(b), 2, 8)
+	expectOverlay(t, a.Union(b), 1, 96)
+	expectOverlay(t, a.Difference(b), 2, 56)
+	expectOverlay(t, b.Difference(a), 3, 32)
+	expectOverlay(t, a.SymDifference(b), 5, 88)
+	// filling the hole
+	c := NewPoly(overlayTestSquare(2, 2, 8, 8), nil, nil)
+	union := a.Union(c)
+	expectOverlay(t, union, 1, 100)
+	expect(t, len(union[0].Holes) == 0)
+}
+
+func TestOverlayMulti(t *testing.T) {
+	a := []*Poly{
+		NewPoly(overlayTestSquare(0, 0, 10, 10), nil, nil),
+		NewPoly(overlayTestSquare(20, 0, 30, 10), nil, nil),
+	}
+	b := []*Poly{NewPoly(overlayTestSquare(5, 4, 25, 6), nil, nil)}
+	expectOverlay(t, Overlay(a, b, OverlayIntersection, nil), 2, 20)
+	expectOverlay(t, Overlay(a, b, OverlayUnion, nil), 1, 220)
+	expectOverlay(t, Overlay(a, b, OverlayDifference, nil), 2, 180)
+	expectOverlay(t, Overlay(a, nil, OverlayUnion, nil), 2, 200)
+	expectOverlay(t, Overlay(nil, b, OverlayIntersection, nil), 0, 0)
+	expectOverlay(t, Overlay(a, []*Poly{new(Poly)}, OverlayDifference, nil),
+		2, 200)
+}
+
+func TestOverlayIndexed(t *testing.T) {
+	circle := func(cx, cy, r float64, n int) []Point {
+		var points []Point
+		for i := 0; i < n; i++ {
+			th := 2 * math.Pi * float64(i) / float64(n)
+			points = append(points, Point{cx + r*math.Cos(th), cy + r*math.Sin(th)})
+		}
+		return append(points, points[0])
+	}
+	for _, op := range []OverlayOp{OverlayIntersection, OverlayUnion,
+		OverlayDifference, OverlaySymDifference} {
+		a := circle(0, 0, 10, 500)
+		b := circle(5, 3, 8, 300)
+		simple := Overlay(
+			[]*Poly{newPolySimple(a, nil)}, []*Poly{newPolySimple(b, nil)},
+			op, nil)
+		indexed := Overlay(
+			[]*Poly{newPolyIndexed(a, nil)}, []*Poly{newPolyIndexed(b, nil)},
+			op, nil)
+		expectOverlay(t, indexed, len(simple), overlayTestArea(simple))
+		expect(t, overlayTestArea(simple) > 0)
+	}
+	// the areas of the operations must agree with each other
+	a := []*Poly{NewPoly(circle(0, 0, 10, 500), nil, nil)}
+	b := []*Poly{NewPoly(circle(5, 3, 8, 300), nil, nil)}
+	inter := overlayTestArea(Overlay(a, b, OverlayIntersection, nil))
+	union := overlayTestArea(Overlay(a, b, OverlayUnion, nil))
+	diff := overlayTestArea(Overlay(a, b, OverlayDifference, nil))
+	xor := overlayTestArea(Overlay(a, b, OverlaySymDifference, nil))
+	expect(t, math.Abs(union+inter-overlayTestArea(a)-overlayTestArea(b)) < 1e-6)
+	expect(t, math.Abs(diff+inter-overlayTestArea(a)) < 1e-6)
+	expect(t, math.Abs(xor+inter-union) < 1e-6)
+	expect(t, OverlaySymDifference.String() == "SymDifference")
+}
+
+func TestOverlayNearlyDegenerate(t *testing.T) {
+	// the vertex of b at (5484.41..., -1207.30...) was calculated by an
+	// earlier overlay, and is only nearly on the edge of a
+	a := NewPoly([]Point{
+		{5435.252578, -1028.311479}, {5274.256257, -1135.909998},
+		{5295.09459, -1195.51386}, {5314.592239942353, -1188.6972124614667},
+		{5418.105747, -1281.113869}, {5450.36476, -1300.436797},
+		{5484.704871, -1206.517913}, {5551.303995, -1131.921957},
+		{5435.252578, -1028.311479},
+	}, nil, nil)
+	b := NewPoly([]Point{
+		{5450.36476, -1300.436797}, {5550.166516, -1336.927897},
+		{5684.506302, -1242.753999}, {5684.242351, -1139.249881},
+		{5592.57338837622, -1139.4836504480961},
+		{5569.387889178357, -1131.0062007148558},
+		{5497.44416, -1089.845882}, {5484.243001, -1139.759909},
+		{5484.415263790109, -1207.3099775903356}, {5450.36476, -1300.436797},
+	}, nil, nil)
+	union := a.Union(b)
+	expect(t, len(union) == 1)
+	area := overlayTestArea(union)
+	expect(t, area > overlayTestArea([]*Poly{a}))
+	expect(t, area > overlayTestArea([]*Poly{b}))
+	inter := overlayTestArea(a.Intersection(b))
+	expect(t, math.Abs(area+inter-overlayTestArea([]*Poly{a})-
+		overlayTestArea([]*Poly{b})) < 1e-6)
+}
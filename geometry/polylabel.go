@@ -0,0 +1,133 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"container/heap"
+	"math"
+)
+
+// ringDistance returns the distance from a point to the nearest edge of a
+// ring, when it's less than the max distance. Only the segments in the max
+// distance are searched.
+func ringDistance(ring Ring, point Point, max float64) float64 {
+	dist := max
+	rect := Rect{
+		Min: Point{X: point.X - max, Y: point.Y - max},
+		Max: Point{X: point.X + max, Y: point.Y + max},
+	}
+	ring.Search(rect, func(seg Segment, index int) bool {
+		if d := seg.DistancePoint(point); d < dist {
+			dist = d
+		}
+		return true
+	})
+	return dist
+}
+
+// edgeDistance returns the distance from a point to the nearest edge of the
+// polygon, which is negative when the point is outside of the polygon.
+func (poly *Poly) edgeDistance(point Point) float64 {
+	// a vertex is on an edge, which makes for a good first max distance
+	first := poly.Exterior.PointAt(0)
+	dist := math.Hypot(point.X-first.X, point.Y-first.Y)
+	dist = ringDistance(poly.Exterior, point, dist)
+	inside := ringContainsPoint(poly.Exterior, point, true).hit
+	for _, hole := range poly.Holes {
+		dist = ringDistance(hole, point, dist)
+		if inside && ringContainsPoint(hole, point, false).hit {
+			inside = false
+		}
+	}
+	if !inside {
+		return -dist
+	}
+	return dist
+}
+
+// labelCell is a square cell for the polylabel search.
+type labelCell struct {
+	center Point
+	half   float64 // half of the size of the cell
+	dist   float64 // distance from the center to the polygon edges
+	max    float64 // max distance from a point in the cell to the edges
+}
+
+func newLabelCell(poly *Poly, center Point, half float64) *labelCell {
+	dist := poly.edgeDistance(center)
+	return &labelCell{center, half, dist, dist + half*math.Sqrt2}
+}
+
+// labelQueue is a max heap of cells ordered by the max distance.
+type labelQueue []*labelCell
+
+func (q labelQueue) Len() int            { return len(q) }
+func (q labelQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q labelQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *labelQueue) Push(x interface{}) { *q = append(*q, x.(*labelCell)) }
+func (q *labelQueue) Pop() interface{} {
+	cell := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return cell
+}
+
+// Polylabel returns the pole of inaccessibility of the polygon, which is the
+// point inside of the polygon that is farthest from its edges, and the
+// distance to the nearest edge. The point is found to within the precision,
+// which is in the units of the coordinates. A precision of zero or less uses
+// one thousandth of the larger side of the polygon rectangle. A polygon
+// without area returns its first point and a zero distance.
+func (poly *Poly) Polylabel(precision float64) (Point, float64) {
+	if poly.Empty() {
+		return Point{}, 0
+	}
+	rect := poly.Rect()
+	width, height := rect.Max.X-rect.Min.X, rect.Max.Y-rect.Min.Y
+	size := math.Min(width, height)
+	if size == 0 {
+		return poly.Exterior.PointAt(0), 0
+	}
+	if precision <= 0 {
+		precision = math.Max(width, height) / 1000
+	}
+	// cover the polygon with square cells
+	half := size / 2
+	var queue labelQueue
+	for x := rect.Min.X; x < rect.Max.X; x += size {
+		for y := rect.Min.Y; y < rect.Max.Y; y += size {
+			queue = append(queue,
+				newLabelCell(poly, Point{X: x + half, Y: y + half}, half))
+		}
+	}
+	heap.Init(&queue)
+	// the first best cells are the centroid and the center of the rectangle
+	best := newLabelCell(poly, poly.Centroid(), 0)
+	if cell := newLabelCell(poly, rect.Center(), 0); cell.dist > best.dist {
+		best = cell
+	}
+	for queue.Len() > 0 {
+		cell := heap.Pop(&queue).(*labelCell)
+		if cell.dist > best.dist {
+			best = cell
+		}
+		// a cell that can't have a better point by more than the precision
+		// is not divided
+		if cell.max-best.dist <= precision {
+			continue
+		}
+		half := cell.half / 2
+		for _, d := range [4][2]float64{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+			center := Point{
+				X: cell.center.X + d[0]*half,
+				Y: cell.center.Y + d[1]*half,
+			}
+			heap.Push(&queue, newLabelCell(poly, center, half))
+		}
+	}
+	if best.dist < 0 {
+		return poly.PointOnSurface(), 0
+	}
+	return best.center, best.dist
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolylabel(t *testing.T) {
+	square := NewPoly([]Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		nil, nil)
+	point, dist := square.Polylabel(0.01)
+	expect(t, math.Abs(point.X-5) <= 0.01 && math.Abs(point.Y-5) <= 0.01)
+	expect(t, math.Abs(dist-5) <= 0.01)
+
+	// the pole of an L shape is in the corner, away from the centroid
+	lshape := NewPoly([]Point{
+		{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 10}, {0, 10}, {0, 0},
+	}, nil, nil)
+	point, dist = lshape.Polylabel(0.001)
+	expect(t, lshape.ContainsPoint(point))
+	expect(t, math.Abs(dist-2*math.Sqrt2/(1+math.Sqrt2)) < 0.001)
+
+	// a hole pushes the pole away from the center
+	holed := NewPoly([]Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		[][]Point{{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}}, nil)
+	point, dist = holed.Polylabel(0.01)
+	expect(t, holed.ContainsPoint(point))
+	expect(t, dist > 1.9)
+	expect(t, math.Abs(holed.edgeDistance(Point{5, 5})+1) < 1e-9)
+
+	// a complex ring uses the segment index
+	var ring []Point
+	for i := 0; i < 360; i++ {
+		a := float64(i) * math.Pi / 180
+		ring = append(ring, Point{100 + 10*math.Cos(a), 50 + 10*math.Sin(a)})
+	}
+	circle := NewPoly(ring, nil, &IndexOptions{Kind: QuadTree, MinPoints: 64})
+	point, dist = circle.Polylabel(0)
+	expect(t, math.Hypot(point.X-100, point.Y-50) < 0.05)
+	expect(t, dist > 9.95)
+
+	flat := NewPoly([]Point{{0, 0}, {4, 0}, {0, 0}}, nil, nil)
+	point, dist = flat.Polylabel(1)
+	expect(t, point == Point{0, 0} && dist == 0)
+}
@@ -0,0 +1,108 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import "math"
+
+// DecodePolyline decodes the values of a Google encoded polyline. Each point
+// is a group of dims values, which are the latitude, the longitude, and an
+// optional third value such as the elevation. The precision is the number of
+// decimal places of the values, which is 5 for Google and 6 for some other
+// routing services. It returns false when the polyline is not valid.
+func DecodePolyline(polyline string, precision, dims int) ([]float64, bool) {
+	if dims < 1 {
+		return nil, false
+	}
+	factor := math.Pow10(precision)
+	var values []float64
+	prev := make([]int64, dims)
+	var n int
+	for i := 0; i < len(polyline); {
+		var result uint64
+		var shift uint
+		for {
+			if i == len(polyline) || shift > 63 {
+				return nil, false
+			}
+			c := int(polyline[i]) - 63
+			i++
+			if c < 0 || c > 63 {
+				return nil, false
+			}
+			result |= uint64(c&0x1f) << shift
+			shift += 5
+			if c < 0x20 {
+				break
+			}
+		}
+		delta := int64(result >> 1)
+		if result&1 != 0 {
+			delta = ^delta
+		}
+		prev[n%dims] += delta
+		values = append(values, float64(prev[n%dims])/factor)
+		n++
+	}
+	if n%dims != 0 {
+		return nil, false
+	}
+	return values, true
+}
+
+// AppendPolyline appends the values as a Google encoded polyline. Each point
+// is a group of dims values, such as the latitude and the longitude.
+func AppendPolyline(dst []byte, values []float64, precision, dims int) []byte {
+	if dims < 1 {
+		return dst
+	}
+	factor := math.Pow10(precision)
+	prev := make([]int64, dims)
+	for i, value := range values {
+		v := int64(math.Round(value * factor))
+		delta := v - prev[i%dims]
+		prev[i%dims] = v
+		zigzag := uint64(delta << 1)
+		if delta < 0 {
+			zigzag = ^zigzag
+		}
+		for zigzag >= 0x20 {
+			dst = append(dst, byte(0x20|zigzag&0x1f)+63)
+			zigzag >>= 5
+		}
+		dst = append(dst, byte(zigzag)+63)
+	}
+	return dst
+}
+
+// ParsePolyline returns a Line from a Google encoded polyline with the
+// precision, such as 5 or 6. It returns false when the polyline is not valid.
+func ParsePolyline(polyline string, precision int, opts *IndexOptions) (
+	*Line, bool,
+) {
+	values, ok := DecodePolyline(polyline, precision, 2)
+	if !ok {
+		return nil, false
+	}
+	points := make([]Point, len(values)/2)
+	for i := range points {
+		points[i] = Point{X: values[i*2+1], Y: values[i*2]}
+	}
+	return NewLine(points, opts), true
+}
+
+// AppendPolyline appends the line as a Google encoded polyline with the
+// precision, such as 5 or 6.
+func (line *Line) AppendPolyline(dst []byte, precision int) []byte {
+	if line == nil {
+		return dst
+	}
+	n := line.NumPoints()
+	values := make([]float64, 0, n*2)
+	for i := 0; i < n; i++ {
+		point := line.PointAt(i)
+		values = append(values, point.Y, point.X)
+	}
+	return AppendPolyline(dst, values, precision, 2)
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import "testing"
+
+func TestPolyline(t *testing.T) {
+	// the example from the Google documentation
+	polyline := "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+	line, ok := ParsePolyline(polyline, 5, DefaultIndexOptions)
+	expect(t, ok)
+	expect(t, line.NumPoints() == 3)
+	expect(t, line.PointAt(0) == P(-120.2, 38.5))
+	expect(t, line.PointAt(1) == P(-120.95, 40.7))
+	expect(t, line.PointAt(2) == P(-126.453, 43.252))
+	expect(t, string(line.AppendPolyline(nil, 5)) == polyline)
+
+	// precision 6
+	line = NewLine([]Point{P(-120.2, 38.5), P(-120.95, 40.7)}, nil)
+	polyline = string(line.AppendPolyline(nil, 6))
+	expect(t, polyline == "_izlhA~rlgdF_{geC~ywl@")
+	line2, ok := ParsePolyline(polyline, 6, nil)
+	expect(t, ok)
+	expect(t, line2.PointAt(1) == P(-120.95, 40.7))
+
+	// three values for each point
+	values := []float64{38.5, -120.2, 100, 40.7, -120.95, -12.5}
+	polyline = string(AppendPolyline(nil, values, 5, 3))
+	values2, ok := DecodePolyline(polyline, 5, 3)
+	expect(t, ok && len(values2) == 6)
+	for i := range values {
+		expect(t, values[i] == values2[i])
+	}
+
+	values, ok = DecodePolyline("", 5, 2)
+	expect(t, ok && len(values) == 0)
+	_, ok = DecodePolyline("_p~iF~ps|U_ulL", 5, 2)
+	expect(t, !ok)
+	_, ok = DecodePolyline("_p~iF~ps|U_", 5, 2)
+	expect(t, !ok)
+	_, ok = DecodePolyline("_p~iF ~ps|U", 5, 2)
+	expect(t, !ok)
+	_, ok = DecodePolyline("_p~iF~ps|U", 5, 0)
+	expect(t, !ok)
+}
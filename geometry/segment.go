@@ -4,6 +4,8 @@
 
 package geometry
 
+import "math"
+
 func eqZero(x float64) bool {
 	return !(x < 0 || x > 0)
 }
@@ -46,6 +48,28 @@ func (seg Segment) ContainsPoint(point Point) bool {
 	return seg.Raycast(point).On
 }
 
+// DistancePoint returns the planar distance from a point to the segment.
+func (seg Segment) DistancePoint(point Point) float64 {
+	x, y := seg.A.X, seg.A.Y
+	dx, dy := seg.B.X-x, seg.B.Y-y
+	if dx != 0 || dy != 0 {
+		t := ((point.X-x)*dx + (point.Y-y)*dy) / (dx*dx + dy*dy)
+		if t > 1 {
+			x, y = seg.B.X, seg.B.Y
+		} else if t > 0 {
+			x, y = x+dx*t, y+dy*t
+		}
+	}
+	return math.Hypot(point.X-x, point.Y-y)
+}
+
+// Orient returns twice the signed area of the triangle a, b, c. It's
+// positive when c is to the left of the line from a to b, negative when c is
+// to the right, and zero when the points are collinear.
+func Orient(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
 // func (seg Segment) Angle() float64 {
 // 	return math.Atan2(seg.B.Y-seg.A.Y, seg.B.X-seg.A.X)
 // }
@@ -361,3 +361,17 @@ func TestSegmentMove(t *testing.T) {
 func TestSegmentRect(t *testing.T) {
 	expect(t, S(12, 13, 11, 12).Rect() == R(11, 12, 12, 13))
 }
+
+func TestSegmentDistancePoint(t *testing.T) {
+	seg := S(0, 0, 10, 0)
+	expect(t, seg.DistancePoint(P(5, 3)) == 3)
+	expect(t, seg.DistancePoint(P(-3, 4)) == 5)
+	expect(t, seg.DistancePoint(P(13, -4)) == 5)
+	expect(t, S(1, 1, 1, 1).DistancePoint(P(4, 5)) == 5)
+}
+
+func TestOrient(t *testing.T) {
+	expect(t, Orient(P(0, 0), P(10, 0), P(5, 5)) == 50)
+	expect(t, Orient(P(0, 0), P(10, 0), P(5, -5)) == -50)
+	expect(t, Orient(P(0, 0), P(10, 0), P(20, 0)) == 0)
+}
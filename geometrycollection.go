@@ -45,6 +45,18 @@ func (g *GeometryCollection) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+func (g *GeometryCollection) AppendWKT(dst []byte) []byte {
+	return appendWKTGeometries(dst, g.children)
+}
+
+func (g *GeometryCollection) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *GeometryCollection) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBGeometries(dst, opts, g.children)
+}
+
 func parseJSONGeometryCollection(
 	keys *parseKeys, opts *ParseOptions,
 ) (Object, error) {
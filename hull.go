@@ -0,0 +1,109 @@
+package geojson
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// ConvexHull returns the smallest convex Polygon that contains all points of
+// an object. Points of Features, collections and Multi* types are included.
+// A LineString is returned when all points are on a line, a Point when there
+// is only one distinct point, and an empty Polygon when there are no points.
+func ConvexHull(obj Object) Object {
+	var points []geometry.Point
+	for _, point := range appendHullPoints(nil, obj) {
+		// skip the empty points, such as from POINT EMPTY
+		if !math.IsNaN(point.X) && !math.IsNaN(point.Y) {
+			points = append(points, point)
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].X != points[j].X {
+			return points[i].X < points[j].X
+		}
+		return points[i].Y < points[j].Y
+	})
+	// remove the duplicates
+	var n int
+	for i := range points {
+		if i == 0 || points[i] != points[n-1] {
+			points[n] = points[i]
+			n++
+		}
+	}
+	points = points[:n]
+	switch len(points) {
+	case 0:
+		return NewPolygon(nil)
+	case 1:
+		return NewPoint(points[0])
+	}
+	// Andrew's monotone chain, which results in a counter-clockwise ring
+	hull := make([]geometry.Point, 0, len(points)+1)
+	for _, point := range points {
+		for len(hull) >= 2 &&
+			geometry.Orient(hull[len(hull)-2], hull[len(hull)-1], point) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, point)
+	}
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		for len(hull) >= lower &&
+			geometry.Orient(hull[len(hull)-2], hull[len(hull)-1], points[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, points[i])
+	}
+	if len(hull) < 4 {
+		// all points are on a line, which has the first and last points
+		// as its ends.
+		return NewLineString(geometry.NewLine(
+			[]geometry.Point{points[0], points[len(points)-1]}, nil,
+		))
+	}
+	return NewPolygon(geometry.NewPoly(hull, nil, nil))
+}
+
+// appendHullPoints appends the points of an object that can be on its
+// convex hull.
+func appendHullPoints(dst []geometry.Point, obj Object) []geometry.Point {
+	obj.ForEach(func(geom Object) bool {
+		switch g := geom.(type) {
+		case *Point:
+			dst = append(dst, g.base)
+		case *SimplePoint:
+			dst = append(dst, g.Point)
+		case *LineString:
+			dst = appendSeriesPoints(dst, &g.base)
+		case *Polygon:
+			if g.base.Exterior != nil {
+				// holes are inside of the exterior
+				dst = appendSeriesPoints(dst, g.base.Exterior)
+			}
+		case *Rect:
+			dst = appendSeriesPoints(dst, g.base)
+		case *Feature:
+			dst = appendHullPoints(dst, g.base)
+		case *Circle:
+			dst = appendHullPoints(dst, g.getObject())
+		default:
+			if !g.Empty() {
+				dst = appendSeriesPoints(dst, g.Rect())
+			}
+		}
+		return true
+	})
+	return dst
+}
+
+func appendSeriesPoints(dst []geometry.Point, series geometry.Series,
+) []geometry.Point {
+	n := series.NumPoints()
+	for i := 0; i < n; i++ {
+		dst = append(dst, series.PointAt(i))
+	}
+	return dst
+}
@@ -0,0 +1,66 @@
+package geojson
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestConvexHull(t *testing.T) {
+	g := expectJSON(t, `{"type":"MultiPoint","coordinates":[
+		[0,0],[10,0],[5,5],[10,10],[0,10],[3,7],[0,5]
+	]}`, nil)
+	expectJSON(t, ConvexHull(g).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]]]}`)
+	g = expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[5,2],[10,10],[0,10],[0,0]],
+		[[1,1],[2,1],[2,2],[1,1]]
+	]}`, nil)
+	expectJSON(t, ConvexHull(g).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]]]}`)
+	g = expectJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[4,-2],[6,3]]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[
+			{"type":"Point","coordinates":[2,5]}
+		]},"properties":{}}
+	]}`, nil)
+	expectJSON(t, ConvexHull(g).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0],[4,-2],[6,3],[2,5],[0,0]]]}`)
+	hull := ConvexHull(NewCircle(P(-112, 33), 1000, 16))
+	_, ok := hull.(*Polygon)
+	expect(t, ok)
+	expect(t, hull.Contains(PO(-112, 33)))
+	expectJSON(t, ConvexHull(RO(1, 2, 3, 4)).JSON(),
+		`{"type":"Polygon","coordinates":[[[1,2],[3,2],[3,4],[1,4],[1,2]]]}`)
+}
+
+func TestConvexHullDegenerate(t *testing.T) {
+	expect(t, ConvexHull(expectJSON(t, `{"type":"MultiPoint","coordinates":[]}`, nil)).Empty())
+	expectJSON(t, ConvexHull(PO(1, 2)).JSON(),
+		`{"type":"Point","coordinates":[1,2]}`)
+	expectJSON(t, ConvexHull(MPO([]geometry.Point{P(1, 2), P(1, 2)})).JSON(),
+		`{"type":"Point","coordinates":[1,2]}`)
+	g := expectJSON(t, `{"type":"MultiPoint","coordinates":[[2,2],[0,0],[3,3],[1,1]]}`, nil)
+	expectJSON(t, ConvexHull(g).JSON(),
+		`{"type":"LineString","coordinates":[[0,0],[3,3]]}`)
+	g, err := Parse(`MULTIPOINT(EMPTY,(1 2),(3 4))`, nil)
+	expect(t, err == nil)
+	expectJSON(t, ConvexHull(g).JSON(),
+		`{"type":"LineString","coordinates":[[1,2],[3,4]]}`)
+}
+
+func TestConvexHullPointCloud(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([]geometry.Point, 1000)
+	for i := range points {
+		points[i] = P(rng.Float64()*20-10, rng.Float64()*20-10)
+	}
+	hull := ConvexHull(MPO(points))
+	expect(t, !hull.(*Polygon).base.Exterior.Clockwise())
+	expect(t, hull.(*Polygon).base.Exterior.Convex())
+	for _, point := range points {
+		expect(t, hull.Intersects(PO(point.X, point.Y)))
+	}
+}
@@ -0,0 +1,171 @@
+package geojson
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// lineMeters returns the distance in meters along the line of each point.
+func lineMeters(line *geometry.Line) []float64 {
+	n := line.NumPoints()
+	dists := make([]float64, n)
+	for i := 1; i < n; i++ {
+		a, b := line.PointAt(i-1), line.PointAt(i)
+		dists[i] = dists[i-1] + geo.DistanceTo(a.Y, a.X, b.Y, b.X)
+	}
+	return dists
+}
+
+// linePoint returns the point, and the extra coordinate values, that is t
+// of the way along the segment at an index. The point is on the great-circle
+// arc of the segment, and the extra values are interpolated linearly.
+func (g *LineString) linePoint(i int, t float64) (geometry.Point, []float64) {
+	a, b := g.base.PointAt(i), g.base.PointAt(i+1)
+	lat, lon := geo.IntermediatePoint(a.Y, a.X, b.Y, b.X, t)
+	point := geometry.Point{X: lon, Y: lat}
+	if g.extra == nil || g.extra.dims == 0 {
+		return point, nil
+	}
+	dims := int(g.extra.dims)
+	values := make([]float64, dims)
+	for k := 0; k < dims; k++ {
+		va := g.extra.values[i*dims+k]
+		vb := g.extra.values[(i+1)*dims+k]
+		values[k] = va + (vb-va)*t
+	}
+	return point, values
+}
+
+// pointValues returns the extra coordinate values of the point at an index.
+func (g *LineString) pointValues(i int) []float64 {
+	if g.extra == nil || g.extra.dims == 0 {
+		return nil
+	}
+	dims := int(g.extra.dims)
+	return g.extra.values[i*dims : (i+1)*dims]
+}
+
+// extraOf returns the extra of the values, with the dimensions of the line.
+func (g *LineString) extraOf(values []float64) *extra {
+	if g.extra == nil || g.extra.dims == 0 {
+		return nil
+	}
+	return &extra{dims: g.extra.dims, values: values,
+		measure: g.extra.measure}
+}
+
+// Interpolate returns the point at a fraction of the length of the line,
+// from 0 at the first point to 1 at the last point. The point is on the
+// great-circle arc of its segment. The Z and M coordinate values of the line
+// are interpolated too. An empty line returns nil.
+func (g *LineString) Interpolate(fraction float64) *Point {
+	dists := lineMeters(&g.base)
+	if len(dists) == 0 {
+		return nil
+	}
+	return g.InterpolateMeters(fraction * dists[len(dists)-1])
+}
+
+// InterpolateMeters returns the point at a distance in meters along the
+// line. The distance is clamped to the length of the line. The Z and M
+// coordinate values of the line are interpolated too. An empty line returns
+// nil.
+func (g *LineString) InterpolateMeters(meters float64) *Point {
+	dists := lineMeters(&g.base)
+	switch len(dists) {
+	case 0:
+		return nil
+	case 1:
+		values := append([]float64(nil), g.pointValues(0)...)
+		return &Point{base: g.base.PointAt(0), extra: g.extraOf(values)}
+	}
+	point, values := g.linePoint(geometry.LinePosition(dists, meters))
+	return &Point{base: point, extra: g.extraOf(values)}
+}
+
+// Locate returns the fraction of the length of the line, and the distance
+// in meters along the line, of the point on the line that is nearest to a
+// point, such as how far a vehicle is along its route.
+func (g *LineString) Locate(point geometry.Point) (fraction, meters float64) {
+	dists := lineMeters(&g.base)
+	n := len(dists)
+	if n < 2 {
+		return 0, 0
+	}
+	best := math.Inf(1)
+	for i := 0; i < n-1; i++ {
+		a, b := g.base.PointAt(i), g.base.PointAt(i+1)
+		d := geo.DistanceToSegment(point.Y, point.X, a.Y, a.X, b.Y, b.X)
+		if d >= best {
+			continue
+		}
+		best = d
+		lat, lon := geo.NearestPointOnSegment(point.Y, point.X, a.Y, a.X,
+			b.Y, b.X)
+		meters = dists[i] + math.Min(geo.DistanceTo(a.Y, a.X, lat, lon),
+			dists[i+1]-dists[i])
+	}
+	if dists[n-1] == 0 {
+		return 0, 0
+	}
+	return meters / dists[n-1], meters
+}
+
+// Substring returns the part of the line between two fractions of its
+// length. The part is reversed when the start is after the end. The Z and M
+// coordinate values of the line are kept, and are interpolated at the ends.
+func (g *LineString) Substring(start, end float64) *LineString {
+	dists := lineMeters(&g.base)
+	if len(dists) == 0 {
+		return NewLineString(geometry.NewLine(nil, nil))
+	}
+	length := dists[len(dists)-1]
+	return g.SubstringMeters(start*length, end*length)
+}
+
+// SubstringMeters returns the part of the line between two distances in
+// meters along the line, such as the section between km 3 and km 5. The
+// part is reversed when the start is after the end. The Z and M coordinate
+// values of the line are kept, and are interpolated at the ends.
+func (g *LineString) SubstringMeters(start, end float64) *LineString {
+	dists := lineMeters(&g.base)
+	if len(dists) < 2 {
+		ng := NewLineString(geometry.NewLine(seriesPoints(&g.base), nil))
+		if len(dists) == 1 {
+			ng.extra = g.extraOf(append([]float64(nil), g.pointValues(0)...))
+		}
+		return ng
+	}
+	reverse := start > end
+	if reverse {
+		start, end = end, start
+	}
+	i, ti := geometry.LinePosition(dists, start)
+	j, tj := geometry.LinePosition(dists, end)
+	first, values := g.linePoint(i, ti)
+	points := []geometry.Point{first}
+	for k := i + 1; k <= j; k++ {
+		points = append(points, g.base.PointAt(k))
+		values = append(values, g.pointValues(k)...)
+	}
+	last, lastValues := g.linePoint(j, tj)
+	if last != points[len(points)-1] || len(points) == 1 {
+		points = append(points, last)
+		values = append(values, lastValues...)
+	}
+	if reverse {
+		dims := len(values) / len(points)
+		for a, b := 0, len(points)-1; a < b; a, b = a+1, b-1 {
+			points[a], points[b] = points[b], points[a]
+			for k := 0; k < dims; k++ {
+				values[a*dims+k], values[b*dims+k] =
+					values[b*dims+k], values[a*dims+k]
+			}
+		}
+	}
+	ng := NewLineString(geometry.NewLine(points, nil))
+	ng.extra = g.extraOf(values)
+	return ng
+}
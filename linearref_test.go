@@ -0,0 +1,76 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestLineStringInterpolate(t *testing.T) {
+	line := expectJSON(t, `{"type":"LineString","coordinates":[
+		[0,0,10],[1,0,20],[2,0,40]
+	]}`, nil).(*LineString)
+	expect(t, line.Interpolate(0.25).JSON() ==
+		`{"type":"Point","coordinates":[0.5,0,15]}`)
+	expect(t, line.Interpolate(0.75).JSON() ==
+		`{"type":"Point","coordinates":[1.5,0,30]}`)
+	expect(t, line.Interpolate(2).JSON() ==
+		`{"type":"Point","coordinates":[2,0,40]}`)
+	meters := geo.DistanceTo(0, 0, 0, 1)
+	expect(t, line.InterpolateMeters(meters).JSON() ==
+		`{"type":"Point","coordinates":[1,0,20]}`)
+
+	plain := expectJSON(t, `{"type":"LineString","coordinates":[
+		[0,0],[0,1]
+	]}`, nil).(*LineString)
+	expect(t, plain.Interpolate(0.5).JSON() ==
+		`{"type":"Point","coordinates":[0,0.5]}`)
+	// the points follow the great circle, which is poleward of a parallel
+	parallel := expectJSON(t, `{"type":"LineString","coordinates":[
+		[-10,60],[10,60]
+	]}`, nil).(*LineString)
+	mid := parallel.Interpolate(0.5).Base()
+	expect(t, mid.Y > 60 && math.Abs(mid.X) < 1e-9)
+	fraction, _ := parallel.Locate(mid)
+	expect(t, math.Abs(fraction-0.5) < 1e-9)
+
+	empty := NewLineString(geometry.NewLine(nil, nil))
+	expect(t, empty.Interpolate(0.5) == nil)
+}
+
+func TestLineStringLocate(t *testing.T) {
+	line := expectJSON(t, `{"type":"LineString","coordinates":[
+		[0,0],[1,0],[2,0]
+	]}`, nil).(*LineString)
+	fraction, meters := line.Locate(P(1.5, 0.1))
+	expect(t, math.Abs(fraction-0.75) < 1e-6)
+	expect(t, math.Abs(meters-geo.DistanceTo(0, 0, 0, 1.5)) < 0.01)
+	fraction, meters = line.Locate(P(-1, 0))
+	expect(t, fraction == 0 && meters == 0)
+	fraction, _ = line.Locate(P(3, 1))
+	expect(t, fraction == 1)
+}
+
+func TestLineStringSubstring(t *testing.T) {
+	line := expectJSON(t, `{"type":"LineString","coordinates":[
+		[0,0,10,1],[1,0,20,2],[2,0,40,3]
+	]}`, nil).(*LineString)
+	expectJSON(t, line.Substring(0.25, 0.75).JSON(), `{"type":"LineString",`+
+		`"coordinates":[[0.5,0,15,1.5],[1,0,20,2],[1.5,0,30,2.5]]}`)
+	expectJSON(t, line.Substring(0.75, 0.25).JSON(), `{"type":"LineString",`+
+		`"coordinates":[[1.5,0,30,2.5],[1,0,20,2],[0.5,0,15,1.5]]}`)
+	expectJSON(t, line.Substring(0, 0.25).JSON(), `{"type":"LineString",`+
+		`"coordinates":[[0,0,10,1],[0.5,0,15,1.5]]}`)
+
+	// the section between km 3 and km 5
+	route := expectJSON(t, `{"type":"LineString","coordinates":[
+		[0,0],[0,0.1]
+	]}`, nil).(*LineString)
+	sub := route.SubstringMeters(3000, 5000)
+	expect(t, sub.NumPoints() == 2)
+	expect(t, math.Abs(sub.Length()-2000) < 0.001)
+	_, meters := route.Locate(sub.Base().PointAt(0))
+	expect(t, math.Abs(meters-3000) < 0.001)
+}
@@ -56,6 +56,19 @@ func (g *LineString) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+func (g *LineString) AppendWKT(dst []byte) []byte {
+	return appendWKTObject(dst, "LINESTRING", g, g.extra)
+}
+
+func (g *LineString) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *LineString) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	dims, measure := extraDims(g.extra)
+	return appendWKBObject(dst, opts, g, dims, measure)
+}
+
 func (g *LineString) Spatial() Spatial {
 	return g
 }
@@ -73,6 +86,9 @@ func (g *LineString) Contains(obj Object) bool {
 }
 
 func (g *LineString) Intersects(obj Object) bool {
+	if obj, ok := obj.(*Circle); ok {
+		return obj.Intersects(g)
+	}
 	return obj.Spatial().IntersectsLine(&g.base)
 }
 
@@ -214,20 +230,20 @@ func (g *LineString) Distance(obj Object) float64 {
 }
 
 func (g *LineString) DistancePoint(point geometry.Point) float64 {
-	return geoDistancePoints(g.Center(), point)
+	return geoDistancePointLine(point, &g.base)
 }
 
 // DistanceRect ..
 func (g *LineString) DistanceRect(rect geometry.Rect) float64 {
-	return geoDistancePoints(g.Center(), rect.Center())
+	return geoDistanceLineRect(&g.base, rect)
 }
 
 func (g *LineString) DistanceLine(line *geometry.Line) float64 {
-	return geoDistancePoints(g.Center(), line.Rect().Center())
+	return geoDistanceLineLine(&g.base, line)
 }
 
 func (g *LineString) DistancePoly(poly *geometry.Poly) float64 {
-	return geoDistancePoints(g.Center(), poly.Rect().Center())
+	return geoDistanceLinePoly(&g.base, poly)
 }
 
 func (g *LineString) Members() string {
@@ -0,0 +1,147 @@
+package geojson
+
+import "github.com/tidwall/geojson/geo"
+
+// The objects with an area, a length, or a perimeter.
+type (
+	areaObject      interface{ Area() float64 }
+	lengthObject    interface{ Length() float64 }
+	perimeterObject interface{ Perimeter() float64 }
+)
+
+var (
+	_ = []areaObject{
+		&Polygon{}, &MultiPolygon{}, &Rect{}, &Circle{}, &Feature{},
+		&GeometryCollection{}, &FeatureCollection{},
+	}
+	_ = []lengthObject{
+		&LineString{}, &MultiLineString{}, &Feature{},
+		&GeometryCollection{}, &FeatureCollection{},
+	}
+	_ = []perimeterObject{
+		&Polygon{}, &MultiPolygon{}, &Rect{}, &Circle{}, &Feature{},
+		&GeometryCollection{}, &FeatureCollection{},
+	}
+)
+
+// Area returns the area of the polygon on the sphere in square meters, with
+// the area of the holes subtracted.
+func (g *Polygon) Area() float64 {
+	if g.Empty() {
+		return 0
+	}
+	area := geo.RingArea(g.base.Exterior)
+	for _, hole := range g.base.Holes {
+		area -= geo.RingArea(hole)
+	}
+	if area < 0 {
+		return 0
+	}
+	return area
+}
+
+// Perimeter returns the length of the exterior and the holes of the polygon
+// on the sphere in meters.
+func (g *Polygon) Perimeter() float64 {
+	if g.Empty() {
+		return 0
+	}
+	perimeter := geo.RingLength(g.base.Exterior)
+	for _, hole := range g.base.Holes {
+		perimeter += geo.RingLength(hole)
+	}
+	return perimeter
+}
+
+// Area returns the area of the rectangle on the sphere in square meters.
+func (g *Rect) Area() float64 {
+	return geo.RectArea(g.base.Min.Y, g.base.Min.X, g.base.Max.Y, g.base.Max.X)
+}
+
+// Perimeter returns the perimeter of the rectangle on the sphere in meters.
+func (g *Rect) Perimeter() float64 {
+	return geo.RectPerimeter(g.base.Min.Y, g.base.Min.X, g.base.Max.Y,
+		g.base.Max.X)
+}
+
+// Area returns the area of the circle on the sphere in square meters, which
+// is exact, and not the area of the polygon that approximates the circle.
+func (g *Circle) Area() float64 {
+	return geo.CapArea(g.meters)
+}
+
+// Perimeter returns the perimeter of the circle on the sphere in meters.
+func (g *Circle) Perimeter() float64 {
+	return geo.CapPerimeter(g.meters)
+}
+
+// Length returns the length of the line on the sphere in meters.
+func (g *LineString) Length() float64 {
+	return geo.LineLength(&g.base)
+}
+
+// Area returns the area of the feature geometry in square meters.
+func (g *Feature) Area() float64 {
+	return objectArea(g.base)
+}
+
+// Length returns the length of the feature geometry in meters.
+func (g *Feature) Length() float64 {
+	return objectLength(g.base)
+}
+
+// Perimeter returns the perimeter of the feature geometry in meters.
+func (g *Feature) Perimeter() float64 {
+	return objectPerimeter(g.base)
+}
+
+// Area returns the sum of the areas of the children in square meters. The
+// area where children overlap is counted for each child.
+func (g *collection) Area() float64 {
+	var area float64
+	for _, child := range g.children {
+		area += objectArea(child)
+	}
+	return area
+}
+
+// Length returns the sum of the lengths of the children in meters.
+func (g *collection) Length() float64 {
+	var length float64
+	for _, child := range g.children {
+		length += objectLength(child)
+	}
+	return length
+}
+
+// Perimeter returns the sum of the perimeters of the children in meters.
+func (g *collection) Perimeter() float64 {
+	var perimeter float64
+	for _, child := range g.children {
+		perimeter += objectPerimeter(child)
+	}
+	return perimeter
+}
+
+// objectArea returns the area of an object, which is zero for an object
+// without an area, such as a Point or a LineString.
+func objectArea(obj Object) float64 {
+	if obj, ok := obj.(areaObject); ok {
+		return obj.Area()
+	}
+	return 0
+}
+
+func objectLength(obj Object) float64 {
+	if obj, ok := obj.(lengthObject); ok {
+		return obj.Length()
+	}
+	return 0
+}
+
+func objectPerimeter(obj Object) float64 {
+	if obj, ok := obj.(perimeterObject); ok {
+		return obj.Perimeter()
+	}
+	return 0
+}
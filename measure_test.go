@@ -0,0 +1,88 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geo"
+)
+
+func measureNear(a, b float64) bool {
+	return math.Abs(a-b) <= math.Abs(b)*1e-9
+}
+
+func TestMeasurePolygon(t *testing.T) {
+	obj := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[2,0],[2,2],[0,2],[0,0]],
+		[[0.5,0.5],[1.5,0.5],[1.5,1.5],[0.5,1.5],[0.5,0.5]]
+	]}`, nil).(*Polygon)
+	area := geo.RectArea(0, 0, 2, 2) - geo.RectArea(0.5, 0.5, 1.5, 1.5)
+	expect(t, measureNear(obj.Area(), area))
+	perimeter := geo.RingLength(obj.Base().Exterior) +
+		geo.RingLength(obj.Base().Holes[0])
+	expect(t, measureNear(obj.Perimeter(), perimeter))
+
+	multi := expectJSON(t, `{"type":"MultiPolygon","coordinates":[
+		[[[0,0],[1,0],[1,1],[0,1],[0,0]]],
+		[[[10,10],[11,10],[11,11],[10,11],[10,10]]]
+	]}`, nil).(*MultiPolygon)
+	area = geo.RectArea(0, 0, 1, 1) + geo.RectArea(10, 10, 11, 11)
+	expect(t, measureNear(multi.Area(), area))
+	expect(t, multi.Length() == 0)
+}
+
+func TestMeasureLineString(t *testing.T) {
+	obj := expectJSON(t,
+		`{"type":"LineString","coordinates":[[0,0],[1,0],[1,1]]}`,
+		nil).(*LineString)
+	length := geo.DistanceTo(0, 0, 0, 1) + geo.DistanceTo(0, 1, 1, 1)
+	expect(t, measureNear(obj.Length(), length))
+
+	multi := expectJSON(t, `{"type":"MultiLineString","coordinates":[
+		[[0,0],[1,0],[1,1]],[[0,0],[1,0],[1,1]]
+	]}`, nil).(*MultiLineString)
+	expect(t, measureNear(multi.Length(), length*2))
+	expect(t, multi.Area() == 0)
+}
+
+func TestMeasureRectCircle(t *testing.T) {
+	rect := RO(0, 0, 1, 1)
+	expect(t, measureNear(rect.Area(), geo.RectArea(0, 0, 1, 1)))
+	expect(t, measureNear(rect.Perimeter(), geo.RectPerimeter(0, 0, 1, 1)))
+	circle := NewCircle(P(-112, 33), 1000, 64)
+	expect(t, measureNear(circle.Area(), geo.CapArea(1000)))
+	expect(t, measureNear(circle.Perimeter(), geo.CapPerimeter(1000)))
+}
+
+func TestMeasureCollection(t *testing.T) {
+	obj := expectJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":[
+			[[0,0],[1,0],[1,1],[0,1],[0,0]]
+		]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[
+			[0,0],[1,0]
+		]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},
+			"properties":{}}
+	]}`, nil).(*FeatureCollection)
+	expect(t, measureNear(obj.Area(), geo.RectArea(0, 0, 1, 1)))
+	expect(t, measureNear(obj.Length(), geo.DistanceTo(0, 0, 0, 1)))
+	expect(t, obj.Perimeter() > obj.Length())
+	feature := obj.Children()[0].(*Feature)
+	expect(t, measureNear(feature.Area(), geo.RectArea(0, 0, 1, 1)))
+}
+
+func TestMeasureEmpty(t *testing.T) {
+	a := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[1,0],[1,1],[0,1],[0,0]]
+	]}`, nil)
+	b := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[5,5],[6,5],[6,6],[5,6],[5,5]]
+	]}`, nil)
+	empty := Intersection(a, b).(*Polygon)
+	expect(t, empty.Empty())
+	expect(t, empty.Area() == 0 && empty.Perimeter() == 0)
+	collapsed := Buffer(a, -1e6, nil)
+	expect(t, objectArea(collapsed) == 0 && objectPerimeter(collapsed) == 0)
+	expect(t, geo.RingArea(nil) == 0 && geo.RingLength(nil) == 0)
+}
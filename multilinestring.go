@@ -56,6 +56,18 @@ func (g *MultiLineString) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+func (g *MultiLineString) AppendWKT(dst []byte) []byte {
+	return appendWKTChildren(dst, "MULTILINESTRING", g.children)
+}
+
+func (g *MultiLineString) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *MultiLineString) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBChildren(dst, opts, wkbMultiLineString, g.children)
+}
+
 func parseJSONMultiLineString(
 	keys *parseKeys, opts *ParseOptions,
 ) (Object, error) {
@@ -45,6 +45,18 @@ func (g *MultiPoint) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+func (g *MultiPoint) AppendWKT(dst []byte) []byte {
+	return appendWKTChildren(dst, "MULTIPOINT", g.children)
+}
+
+func (g *MultiPoint) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *MultiPoint) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBChildren(dst, opts, wkbMultiPoint, g.children)
+}
+
 func parseJSONMultiPoint(keys *parseKeys, opts *ParseOptions) (Object, error) {
 	var g MultiPoint
 	var err error
@@ -55,6 +55,18 @@ func (g *MultiPolygon) MarshalJSON() ([]byte, error) {
 	return g.AppendJSON(nil), nil
 }
 
+func (g *MultiPolygon) AppendWKT(dst []byte) []byte {
+	return appendWKTChildren(dst, "MULTIPOLYGON", g.children)
+}
+
+func (g *MultiPolygon) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *MultiPolygon) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBChildren(dst, opts, wkbMultiPolygon, g.children)
+}
+
 func parseJSONMultiPolygon(
 	keys *parseKeys, opts *ParseOptions,
 ) (Object, error) {
@@ -0,0 +1,351 @@
+package geojson
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// nearestPart is a point, a line, or a polygon of an object. A point with a
+// radius is a circle.
+type nearestPart struct {
+	rect   geometry.Rect
+	point  geometry.Point
+	radius float64
+	line   *geometry.Line
+	poly   *geometry.Poly
+}
+
+// series returns the lines and rings of the part.
+func (part *nearestPart) series() []geometry.Series {
+	switch {
+	case part.line != nil:
+		return []geometry.Series{part.line}
+	case part.poly != nil:
+		var series []geometry.Series
+		for _, ring := range polyRings(part.poly) {
+			series = append(series, ring)
+		}
+		return series
+	}
+	return nil
+}
+
+// first returns the first point of the part.
+func (part *nearestPart) first() geometry.Point {
+	switch {
+	case part.line != nil:
+		return part.line.PointAt(0)
+	case part.poly != nil:
+		return part.poly.Exterior.PointAt(0)
+	}
+	return part.point
+}
+
+// appendNearestParts appends the non-empty parts of an object.
+func appendNearestParts(parts []nearestPart, obj Object) []nearestPart {
+	if obj.Empty() {
+		return parts
+	}
+	part := nearestPart{rect: obj.Rect()}
+	switch obj := obj.(type) {
+	case *Point, *SimplePoint:
+		part.point = obj.Center()
+		parts = append(parts, part)
+	case *LineString:
+		part.line = obj.Base()
+		parts = append(parts, part)
+	case *Polygon:
+		part.poly = obj.Base()
+		parts = append(parts, part)
+	case *Rect:
+		part.poly = &geometry.Poly{Exterior: obj.Base()}
+		parts = append(parts, part)
+	case *Circle:
+		part.point = obj.Center()
+		part.radius = math.Max(obj.meters, 0)
+		parts = append(parts, part)
+	case *Feature:
+		parts = appendNearestParts(parts, obj.Base())
+	case Collection:
+		for _, child := range obj.Children() {
+			parts = appendNearestParts(parts, child)
+		}
+	}
+	return parts
+}
+
+// NearestPoints returns the point of each object that is nearest to the
+// other object, such as where a location snaps onto the nearest road. The
+// points are geodesically correct, where segments are great-circle arcs.
+// When the objects intersect, both points are the same point, which is in
+// both objects. An empty object uses the center of its rectangle.
+func NearestPoints(a, b Object) (geometry.Point, geometry.Point) {
+	partsA := appendNearestParts(nil, a)
+	partsB := appendNearestParts(nil, b)
+	if len(partsA) == 0 {
+		center := a.Center()
+		partsA = append(partsA, nearestPart{rect: center.Rect(), point: center})
+	}
+	if len(partsB) == 0 {
+		center := b.Center()
+		partsB = append(partsB, nearestPart{rect: center.Rect(), point: center})
+	}
+	best := math.Inf(1)
+	var nearA, nearB geometry.Point
+	for i := range partsA {
+		for j := range partsB {
+			// skip the parts that can't be nearer than the best
+			if geoRectDistanceBound(partsA[i].rect, partsB[j].rect) >= best {
+				continue
+			}
+			pa, pb, dist := nearestParts(&partsA[i], &partsB[j], best)
+			if dist < best {
+				best, nearA, nearB = dist, pa, pb
+			}
+			if best == 0 {
+				return nearA, nearB
+			}
+		}
+	}
+	return nearA, nearB
+}
+
+// nearestParts returns the nearest points of two parts, and the distance
+// between them, or a distance of best or more when they're not nearer than
+// best.
+func nearestParts(a, b *nearestPart, best float64) (
+	geometry.Point, geometry.Point, float64,
+) {
+	// a circle is its center, which is then moved toward the other part
+	pa, pb, dist := nearestCores(a, b, best+a.radius+b.radius)
+	if a.radius == 0 && b.radius == 0 {
+		return pa, pb, dist
+	}
+	if dist <= a.radius+b.radius {
+		// a point that is in both parts
+		switch {
+		case b.radius == 0:
+			return pb, pb, 0
+		case a.radius == 0:
+			return pa, pa, 0
+		}
+		s := math.Max(0, math.Min(dist-b.radius, a.radius))
+		p := geoAlong(pa, pb, s)
+		return p, p, 0
+	}
+	return geoAlong(pa, pb, a.radius), geoAlong(pb, pa, b.radius),
+		dist - a.radius - b.radius
+}
+
+// geoAlong returns the point that is a distance in meters from a point,
+// toward another point.
+func geoAlong(from, to geometry.Point, meters float64) geometry.Point {
+	if meters <= 0 {
+		return from
+	}
+	bearing := geo.BearingTo(from.Y, from.X, to.Y, to.X)
+	lat, lon := geo.DestinationPoint(from.Y, from.X, meters, bearing)
+	return geometry.Point{X: lon, Y: lat}
+}
+
+// nearestCores returns the nearest points of two parts, without the radius
+// of a circle.
+func nearestCores(a, b *nearestPart, best float64) (
+	geometry.Point, geometry.Point, float64,
+) {
+	if p, ok := nearestIntersection(a, b); ok {
+		return p, p, 0
+	}
+	seriesA, seriesB := a.series(), b.series()
+	var pa, pb geometry.Point
+	switch {
+	case len(seriesA) == 0 && len(seriesB) == 0:
+		pa, pb = a.point, b.point
+		return pa, pb, geoDistancePoints(pa, pb)
+	case len(seriesA) == 0:
+		pa = a.point
+		for _, series := range seriesB {
+			if p, d := nearestPointSeries(pa, series, best); d < best {
+				pb, best = p, d
+			}
+		}
+	case len(seriesB) == 0:
+		pb = b.point
+		for _, series := range seriesA {
+			if p, d := nearestPointSeries(pb, series, best); d < best {
+				pa, best = p, d
+			}
+		}
+	default:
+		for _, sa := range seriesA {
+			for _, sb := range seriesB {
+				p, q, d := nearestSeries(sa, sb, best)
+				if d < best {
+					pa, pb, best = p, q, d
+				}
+			}
+		}
+	}
+	return pa, pb, best
+}
+
+// nearestIntersection returns a point that is in both parts, when they
+// intersect. The circles are their centers.
+func nearestIntersection(a, b *nearestPart) (geometry.Point, bool) {
+	// a part that is inside of a polygon has all of its points inside, when
+	// its edges do not cross the polygon edges
+	if a.poly != nil && a.poly.IntersectsPoint(b.first()) {
+		return b.first(), true
+	}
+	if b.poly != nil && b.poly.IntersectsPoint(a.first()) {
+		return a.first(), true
+	}
+	seriesA, seriesB := a.series(), b.series()
+	for _, sa := range seriesA {
+		for _, sb := range seriesB {
+			if p, ok := seriesIntersection(sa, sb); ok {
+				return p, true
+			}
+		}
+	}
+	return geometry.Point{}, false
+}
+
+// seriesIntersection returns a point where the segments of two series
+// cross.
+func seriesIntersection(a, b geometry.Series) (geometry.Point, bool) {
+	if a.NumSegments() > b.NumSegments() {
+		a, b = b, a
+	}
+	var point geometry.Point
+	var found bool
+	n := a.NumSegments()
+	for i := 0; i < n && !found; i++ {
+		seg := a.SegmentAt(i)
+		b.Search(seg.Rect(), func(other geometry.Segment, _ int) bool {
+			if seg.IntersectsSegment(other) {
+				point, found = segmentIntersection(seg, other), true
+			}
+			return !found
+		})
+	}
+	return point, found
+}
+
+// segmentIntersection returns a point where two segments, which intersect,
+// cross. Collinear segments return an end point that is on the other
+// segment.
+func segmentIntersection(a, b geometry.Segment) geometry.Point {
+	dax, day := a.B.X-a.A.X, a.B.Y-a.A.Y
+	dbx, dby := b.B.X-b.A.X, b.B.Y-b.A.Y
+	denom := dax*dby - day*dbx
+	if denom != 0 {
+		t := ((b.A.X-a.A.X)*dby - (b.A.Y-a.A.Y)*dbx) / denom
+		t = math.Max(0, math.Min(1, t))
+		return geometry.Point{X: a.A.X + dax*t, Y: a.A.Y + day*t}
+	}
+	for _, p := range []geometry.Point{a.A, a.B} {
+		if b.ContainsPoint(p) {
+			return p
+		}
+	}
+	return b.A
+}
+
+// nearestPointSeries returns the point of the series that is nearest to a
+// point, and the distance, when it's nearer than best.
+func nearestPointSeries(point geometry.Point, series geometry.Series,
+	best float64,
+) (geometry.Point, float64) {
+	var near geometry.Point
+	if series.NumSegments() == 0 {
+		if series.NumPoints() > 0 {
+			near = series.PointAt(0)
+			if d := geoDistancePoints(point, near); d < best {
+				return near, d
+			}
+		}
+		return near, best
+	}
+	best = geoSearchNearest(series, point.Rect(), best,
+		func(seg geometry.Segment) float64 {
+			d := geoDistancePointSegment(point, seg)
+			if d < best {
+				near = nearestOnSegment(point, seg)
+				best = d
+			}
+			return d
+		},
+	)
+	return near, best
+}
+
+// nearestSeries returns the nearest points of two series, and the distance,
+// when they're nearer than best. The series do not cross.
+func nearestSeries(a, b geometry.Series, best float64) (
+	geometry.Point, geometry.Point, float64,
+) {
+	swap := a.NumSegments() > b.NumSegments()
+	if swap {
+		a, b = b, a
+	}
+	var pa, pb geometry.Point
+	if a.NumSegments() == 0 {
+		if a.NumPoints() == 0 {
+			return pa, pb, best
+		}
+		pa = a.PointAt(0)
+		pb, best = nearestPointSeries(pa, b, best)
+	} else {
+		n := a.NumSegments()
+		for i := 0; i < n && best > 0; i++ {
+			seg := a.SegmentAt(i)
+			best = geoSearchNearest(b, seg.Rect(), best,
+				func(other geometry.Segment) float64 {
+					p, q, d := nearestSegments(seg, other)
+					if d < best {
+						pa, pb, best = p, q, d
+					}
+					return d
+				},
+			)
+		}
+	}
+	if swap {
+		pa, pb = pb, pa
+	}
+	return pa, pb, best
+}
+
+// nearestSegments returns the nearest points of two segments, which do not
+// cross, and the distance between them. The nearest points include at least
+// one of the end points.
+func nearestSegments(a, b geometry.Segment) (
+	geometry.Point, geometry.Point, float64,
+) {
+	pairs := [4][2]geometry.Point{
+		{a.A, nearestOnSegment(a.A, b)},
+		{a.B, nearestOnSegment(a.B, b)},
+		{nearestOnSegment(b.A, a), b.A},
+		{nearestOnSegment(b.B, a), b.B},
+	}
+	var pa, pb geometry.Point
+	best := math.Inf(1)
+	for _, pair := range pairs {
+		if d := geoDistancePoints(pair[0], pair[1]); d < best {
+			pa, pb, best = pair[0], pair[1], d
+		}
+	}
+	return pa, pb, best
+}
+
+// nearestOnSegment returns the point of the segment that is nearest to a
+// point.
+func nearestOnSegment(point geometry.Point, seg geometry.Segment,
+) geometry.Point {
+	lat, lon := geo.NearestPointOnSegment(point.Y, point.X, seg.A.Y, seg.A.X,
+		seg.B.Y, seg.B.X)
+	return geometry.Point{X: lon, Y: lat}
+}
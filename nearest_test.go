@@ -0,0 +1,125 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geo"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func nearEq(a, b geometry.Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestNearestPoints(t *testing.T) {
+	// a location that snaps onto the nearest road
+	road := expectJSON(t, `{"type":"LineString","coordinates":[
+		[-10,0],[0,0],[0,10]
+	]}`, nil)
+	location := NewPoint(P(-3, 1))
+	pa, pb := NearestPoints(location, road)
+	expect(t, pa == P(-3, 1))
+	expect(t, nearEq(pb, P(-3, 0)))
+	expect(t, math.Abs(geoDistancePoints(pa, pb)-location.Distance(road)) <
+		1e-6)
+	pa, pb = NearestPoints(road, location)
+	expect(t, nearEq(pa, P(-3, 0)) && pb == P(-3, 1))
+
+	// the nearest points of a line and a polygon
+	poly := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[5,5],[8,5],[8,8],[5,8],[5,5]]
+	]}`, nil)
+	line := expectJSON(t, `{"type":"LineString","coordinates":[
+		[1,3],[3,3],[3,9]
+	]}`, nil)
+	pa, pb = NearestPoints(line, poly)
+	// the degrees of longitude are shorter at the higher latitudes
+	expect(t, pb == P(5, 8))
+	expect(t, math.Abs(pa.X-3) < 1e-9 && pa.Y > 8 && pa.Y < 8.01)
+	expect(t, math.Abs(geoDistancePoints(pa, pb)-line.Distance(poly)) < 1e-6)
+
+	// the objects intersect
+	cross := expectJSON(t, `{"type":"LineString","coordinates":[
+		[4,6],[9,6]
+	]}`, nil)
+	pa, pb = NearestPoints(cross, poly)
+	expect(t, pa == pb && poly.Intersects(NewPoint(pa)))
+	pa, pb = NearestPoints(poly, NewPoint(P(6, 6)))
+	expect(t, pa == P(6, 6) && pb == P(6, 6))
+	pa, pb = NearestPoints(poly, RO(6, 6, 20, 20))
+	expect(t, pa == pb)
+
+	// a circle is measured from its edge
+	circle := NewCircle(P(0, 0), 1000, 64)
+	far := NewPoint(P(1, 0))
+	pa, pb = NearestPoints(circle, far)
+	expect(t, pb == P(1, 0))
+	expect(t, math.Abs(geoDistancePoints(P(0, 0), pa)-1000) < 1e-6)
+	expect(t, math.Abs(pa.Y) < 1e-9 && pa.X > 0)
+	pa, pb = NearestPoints(circle, NewPoint(P(0.001, 0)))
+	expect(t, pa == pb && pa == P(0.001, 0))
+	pa, pb = NearestPoints(circle, NewCircle(P(0.01, 0), 1000, 64))
+	expect(t, pa == pb)
+
+	// the nearest child of a collection
+	fc := expectJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[50,50]},
+			"properties":{}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[2,1]},
+			"properties":{}}
+	]}`, nil)
+	pa, pb = NearestPoints(fc, road)
+	expect(t, pa == P(2, 1))
+	expect(t, math.Abs(pb.X) < 1e-9 && math.Abs(pb.Y-1) < 0.01)
+
+	// an empty object is the center of its rectangle
+	empty := expectJSON(t, `{"type":"GeometryCollection","geometries":[]}`,
+		nil)
+	pa, _ = NearestPoints(empty, road)
+	expect(t, pa == empty.Center())
+}
+
+func TestNearestPointsIndexed(t *testing.T) {
+	// a long line with a segment index
+	var points []geometry.Point
+	for i := 0; i <= 1000; i++ {
+		x := float64(i) / 10
+		points = append(points, geometry.Point{X: x, Y: math.Sin(x)})
+	}
+	line := NewLineString(geometry.NewLine(points, &geometry.IndexOptions{
+		Kind: geometry.QuadTree, MinPoints: 64,
+	}))
+	for _, point := range []geometry.Point{P(25, 3), P(75.5, -2), P(-1, 0)} {
+		pa, pb := NearestPoints(NewPoint(point), line)
+		expect(t, pa == point)
+		dist := geo.DistanceTo(pa.Y, pa.X, pb.Y, pb.X)
+		expect(t, math.Abs(dist-NewPoint(point).Distance(line)) < 1e-6)
+	}
+}
+
+func TestNearestPointsArcs(t *testing.T) {
+	// the first segment bulges toward the pole, past its rectangle
+	line := NewLineString(indexedLine([]geometry.Point{{X: -60, Y: 70},
+		{X: 60, Y: 70}, {X: 0, Y: 77}}, geometry.Point{X: 0, Y: 77.5}))
+	pa, pb := NearestPoints(PO(0, 80), line)
+	expect(t, pa == P(0, 80))
+	expect(t, math.Abs(pb.X) < 1e-6 && math.Abs(pb.Y-79.69) < 0.01)
+	expect(t, math.Abs(geoDistancePoints(pa, pb)-
+		PO(0, 80).Distance(line)) < 1e-6)
+
+	// a part that is across the antimeridian, after a farther part
+	near := indexedLine([]geometry.Point{{X: -179.99, Y: 0}},
+		geometry.Point{X: -179.99, Y: 10})
+	far := geometry.NewLine([]geometry.Point{{X: 178, Y: 0}, {X: 178, Y: 1}},
+		nil)
+	gc := new(GeometryCollection)
+	gc.children = []Object{NewLineString(far), NewLineString(near)}
+	gc.parseInitRectIndex(DefaultParseOptions)
+	pa, pb = NearestPoints(PO(179.99, 0.5), gc)
+	expect(t, pa == P(179.99, 0.5))
+	expect(t, math.Abs(pb.X+179.99) < 1e-9 && math.Abs(pb.Y-0.5) < 1e-6)
+	expect(t, geoDistancePoints(pa, pb) < 2300)
+	expect(t, math.Abs(geoDistancePoints(pa, pb)-
+		PO(179.99, 0.5).Distance(gc)) < 1e-6)
+}
@@ -25,6 +25,8 @@ var (
 	errGeometriesMissing        = errors.New("missing geometries")
 	errGeometriesInvalid        = errors.New("invalid geometries")
 	errCircleRadiusUnitsInvalid = errors.New("invalid circle radius units")
+	errCircleRadiusMissing      = errors.New("missing circle radius")
+	errCircleRadiusInvalid      = errors.New("invalid circle radius")
 )
 
 // Object is a GeoJSON type
@@ -37,7 +39,10 @@ type Object interface {
 	Within(other Object) bool
 	Intersects(other Object) bool
 	AppendJSON(dst []byte) []byte
+	AppendWKT(dst []byte) []byte
+	AppendWKB(dst []byte, opts *WKBOptions) []byte
 	JSON() string
+	WKT() string
 	String() string
 	Distance(obj Object) float64
 	NumPoints() int
@@ -59,6 +64,7 @@ type Collection interface {
 	Children() []Object
 	Indexed() bool
 	Search(rect geometry.Rect, iter func(child Object) bool)
+	SearchRects(rects []geometry.Rect, iter func(child Object) bool)
 }
 
 var _ = []Collection{
@@ -69,6 +75,9 @@ var _ = []Collection{
 type extra struct {
 	dims   byte      // number of extra coordinate values, 1 or 2
 	values []float64 // extra coordinate values
+	// single extra value is an M (measure) rather than a Z, such as from a
+	// well-known text "POINT M" or a well-known binary XYM geometry.
+	measure bool
 	// valid json object that includes extra members such as
 	// "bbox", "id", "properties", and foreign members
 	members string
@@ -98,6 +107,17 @@ type ParseOptions struct {
 	// DisableCircleType disables the special Circle syntax that is unique to
 	// only Tile38.
 	DisableCircleType bool
+	// CircleSteps is the number of points in the polygon that approximates
+	// a Circle. The default is 64.
+	CircleSteps int
+	// CircleUnits are extra "radius_units" for a Circle, which map the name
+	// of the units to the number of meters in one unit. These are checked
+	// before the builtin "m", "km", "ft", "mi", and "nmi" units.
+	CircleUnits map[string]float64
+	// KeepCircleMembers option will cause a Circle to keep the other members
+	// of its Feature, such as "id" and "properties", and to write them back
+	// with the radius in its original units.
+	KeepCircleMembers bool
 	// AllowRects options will force to parse and return the Rect type when a
 	// geojson polygon only consists of a perfect rectangle, where there are
 	// exactly 5 points with the first point being the min x/y and the
@@ -112,6 +132,7 @@ var DefaultParseOptions = &ParseOptions{
 	RequireValid:      false,
 	AllowSimplePoints: false,
 	DisableCircleType: false,
+	CircleSteps:       64,
 	AllowRects:        false,
 }
 
@@ -128,15 +149,13 @@ func Parse(data string, opts *ParseOptions) (Object, error) {
 		}
 		switch data[0] {
 		default:
-			// well-known text is not supported yet
-			return nil, errDataInvalid
+			return parseWKT(data, opts)
 		case 0, 1:
 			if i > 0 {
 				// 0x00 or 0x01 must be the first bytes
 				return nil, errDataInvalid
 			}
-			// well-known binary is not supported yet
-			return nil, errDataInvalid
+			return parseWKB(data, opts)
 		case ' ', '\t', '\n', '\r':
 			// strip whitespace
 			data = data[1:]
@@ -0,0 +1,69 @@
+package geojson
+
+import "github.com/tidwall/geojson/geometry"
+
+// Intersection returns the area that is covered by both objects.
+//
+// The overlay functions work on the area of Polygon, MultiPolygon, Rect and
+// Circle objects, and Features of those. Other objects do not have an area
+// and are treated as empty. The result is a Polygon or a MultiPolygon, which
+// only keeps the X and Y coordinates.
+func Intersection(a, b Object) Object {
+	return overlay(a, b, geometry.OverlayIntersection)
+}
+
+// Union returns the area that is covered by either object.
+func Union(a, b Object) Object {
+	return overlay(a, b, geometry.OverlayUnion)
+}
+
+// Difference returns the area of the first object that is not covered by the
+// second object.
+func Difference(a, b Object) Object {
+	return overlay(a, b, geometry.OverlayDifference)
+}
+
+// SymDifference returns the area that is covered by exactly one of the
+// objects.
+func SymDifference(a, b Object) Object {
+	return overlay(a, b, geometry.OverlaySymDifference)
+}
+
+func overlay(a, b Object, op geometry.OverlayOp) Object {
+	return polysObject(geometry.Overlay(objectPolys(a), objectPolys(b), op,
+		nil))
+}
+
+// objectPolys returns the polygons that make up the area of an object.
+func objectPolys(obj Object) []*geometry.Poly {
+	switch g := obj.(type) {
+	case *Polygon:
+		return []*geometry.Poly{&g.base}
+	case *Rect:
+		return []*geometry.Poly{{Exterior: g.base}}
+	case *MultiPolygon:
+		var polys []*geometry.Poly
+		for _, child := range g.children {
+			polys = append(polys, objectPolys(child)...)
+		}
+		return polys
+	case *Circle:
+		return objectPolys(g.getObject())
+	case *Feature:
+		return objectPolys(g.base)
+	}
+	return nil
+}
+
+// polysObject returns a Polygon, or a MultiPolygon when there's more than one
+// polygon.
+func polysObject(polys []*geometry.Poly) Object {
+	switch len(polys) {
+	case 0:
+		return NewPolygon(nil)
+	case 1:
+		return NewPolygon(polys[0])
+	default:
+		return NewMultiPolygon(polys)
+	}
+}
@@ -0,0 +1,45 @@
+package geojson
+
+import "testing"
+
+func TestOverlay(t *testing.T) {
+	a := expectJSON(t, `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]]]}`, nil)
+	b := RO(5, 5, 15, 15)
+	expectJSON(t, Intersection(a, b).JSON(),
+		`{"type":"Polygon","coordinates":[[[10,5],[10,10],[5,10],[5,5],[10,5]]]}`)
+	expectJSON(t, Union(a, b).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,5],[15,5],[15,15],[5,15],[5,10],[0,10],[0,0]]]}`)
+	expectJSON(t, Difference(a, b).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,5],[5,5],[5,10],[0,10],[0,0]]]}`)
+	xor := SymDifference(a, b)
+	expect(t, len(xor.(*MultiPolygon).Children()) == 2)
+	expect(t, xor.Intersects(PO(1, 1)))
+	expect(t, xor.Intersects(PO(14, 14)))
+	expect(t, !xor.Intersects(PO(7, 7)))
+
+	// multipolygons with holes
+	mp := expectJSON(t, `{"type":"MultiPolygon","coordinates":[
+		[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[8,2],[8,8],[2,8],[2,2]]],
+		[[[20,0],[30,0],[30,10],[20,10],[20,0]]]
+	]}`, nil)
+	strip := RO(5, 4, 25, 6)
+	inter := Intersection(mp, strip)
+	expect(t, len(inter.(*MultiPolygon).Children()) == 2)
+	expect(t, inter.Intersects(PO(9, 5)))
+	expect(t, !inter.Intersects(PO(5, 5)))
+	expect(t, !inter.Intersects(PO(15, 5)))
+	union := Union(mp, strip)
+	_, ok := union.(*Polygon)
+	expect(t, ok)
+	expect(t, union.Intersects(PO(15, 5)))
+	expect(t, union.Intersects(PO(5, 5)))
+	expect(t, !union.Intersects(PO(5, 3)))
+
+	// features and empty results
+	f := expectJSON(t, `{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]]]},"properties":{}}`, nil)
+	expect(t, Difference(f, a).Empty())
+	expect(t, Intersection(a, PO(5, 5)).Empty())
+	expect(t, Union(a, PO(5, 5)).JSON() == Union(a, RO(20, 20, 20, 20)).JSON())
+	circle := NewCircle(P(5, 5), 100000, 64)
+	expect(t, Intersection(circle, a).Intersects(PO(5, 5)))
+}
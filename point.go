@@ -10,6 +10,7 @@ import (
 type Point struct {
 	base  geometry.Point
 	extra *extra
+	empty bool // an empty point, which has NaN coordinates
 }
 
 func NewPoint(point geometry.Point) *Point {
@@ -28,7 +29,17 @@ func (g *Point) ForEach(iter func(geom Object) bool) bool {
 }
 
 func (g *Point) Empty() bool {
-	return g.base.Empty()
+	return g.empty || g.base.Empty()
+}
+
+// newEmptyPoint returns an empty point, such as from a well-known text
+// "POINT EMPTY".
+func newEmptyPoint(ex *extra) *Point {
+	return &Point{
+		base:  geometry.Point{X: math.NaN(), Y: math.NaN()},
+		extra: ex,
+		empty: true,
+	}
 }
 
 func (g *Point) Valid() bool {
@@ -53,7 +64,11 @@ func (g *Point) Base() geometry.Point {
 
 func (g *Point) AppendJSON(dst []byte) []byte {
 	dst = append(dst, `{"type":"Point","coordinates":`...)
-	dst = appendJSONPoint(dst, g.base, g.extra, 0)
+	if g.empty {
+		dst = append(dst, "[]"...)
+	} else {
+		dst = appendJSONPoint(dst, g.base, g.extra, 0)
+	}
 	dst = g.extra.appendJSONExtra(dst, false)
 	dst = append(dst, '}')
 	return dst
@@ -71,6 +86,19 @@ func (g *Point) String() string {
 	return string(g.AppendJSON(nil))
 }
 
+func (g *Point) AppendWKT(dst []byte) []byte {
+	return appendWKTObject(dst, "POINT", g, g.extra)
+}
+
+func (g *Point) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *Point) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	dims, measure := extraDims(g.extra)
+	return appendWKBObject(dst, opts, g, dims, measure)
+}
+
 func (g *Point) Within(obj Object) bool {
 	return obj.Contains(g)
 }
@@ -131,6 +159,13 @@ func (g *Point) Z() float64 {
 
 func parseJSONPoint(keys *parseKeys, opts *ParseOptions) (Object, error) {
 	var o Object
+	if keys.rCoordinates.IsArray() && keys.rCoordinates.Get("#").Int() == 0 {
+		g := newEmptyPoint(nil)
+		if err := parseBBoxAndExtras(&g.extra, keys, opts); err != nil {
+			return nil, err
+		}
+		return g, nil
+	}
 	base, extra, err := parseJSONPointCoords(keys, gjson.Result{}, opts)
 	if err != nil {
 		return nil, err
@@ -222,15 +257,15 @@ func (g *Point) DistancePoint(point geometry.Point) float64 {
 }
 
 func (g *Point) DistanceRect(rect geometry.Rect) float64 {
-	return geoDistancePoints(g.Center(), rect.Center())
+	return geoDistancePointRect(g.base, rect)
 }
 
 func (g *Point) DistanceLine(line *geometry.Line) float64 {
-	return geoDistancePoints(g.Center(), line.Rect().Center())
+	return geoDistancePointLine(g.base, line)
 }
 
 func (g *Point) DistancePoly(poly *geometry.Poly) float64 {
-	return geoDistancePoints(g.Center(), poly.Rect().Center())
+	return geoDistancePointPoly(g.base, poly)
 }
 
 // IsSimple returns true if the Point can be converted to a SimplePoint
@@ -68,6 +68,19 @@ func (g *Polygon) String() string {
 	return string(g.AppendJSON(nil))
 }
 
+func (g *Polygon) AppendWKT(dst []byte) []byte {
+	return appendWKTObject(dst, "POLYGON", g, g.extra)
+}
+
+func (g *Polygon) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *Polygon) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	dims, measure := extraDims(g.extra)
+	return appendWKBObject(dst, opts, g, dims, measure)
+}
+
 func (g *Polygon) Spatial() Spatial {
 	return g
 }
@@ -81,6 +94,9 @@ func (g *Polygon) Within(obj Object) bool {
 }
 
 func (g *Polygon) Contains(obj Object) bool {
+	if obj, ok := obj.(*Circle); ok {
+		return obj.withinPoly(&g.base)
+	}
 	return obj.Spatial().WithinPoly(&g.base)
 }
 
@@ -101,6 +117,9 @@ func (g *Polygon) WithinPoly(poly *geometry.Poly) bool {
 }
 
 func (g *Polygon) Intersects(obj Object) bool {
+	if obj, ok := obj.(*Circle); ok {
+		return obj.Intersects(g)
+	}
 	return obj.Spatial().IntersectsPoly(&g.base)
 }
 
@@ -129,7 +148,6 @@ func (g *Polygon) NumPoints() int {
 }
 
 func parseJSONPolygon(keys *parseKeys, opts *ParseOptions) (Object, error) {
-	var o Object
 	coords, extra, err := parseJSONPolygonCoords(keys, gjson.Result{}, opts)
 	if err != nil {
 		return nil, err
@@ -142,15 +160,28 @@ func parseJSONPolygon(keys *parseKeys, opts *ParseOptions) (Object, error) {
 			return nil, errCoordinatesInvalid // must be a linear ring
 		}
 	}
+	if err := parseBBoxAndExtras(&extra, keys, opts); err != nil {
+		return nil, err
+	}
+	o := parsePolygonObject(coords, extra, opts)
+	if opts.RequireValid {
+		if !o.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	return o, nil
+}
+
+// parsePolygonObject returns a Polygon, or a Rect when the AllowRects option
+// is provided and the coordinates form a simple rectangle.
+func parsePolygonObject(
+	coords [][]geometry.Point, extra *extra, opts *ParseOptions,
+) Object {
 	exterior := coords[0]
 	var holes [][]geometry.Point
 	if len(coords) > 1 {
 		holes = coords[1:]
 	}
-	gopts := toGeometryOpts(opts)
-	if err := parseBBoxAndExtras(&extra, keys, opts); err != nil {
-		return nil, err
-	}
 	if extra == nil && opts.AllowRects &&
 		len(holes) == 0 && len(exterior) == 5 &&
 		exterior[0].X < exterior[1].X &&
@@ -162,23 +193,14 @@ func parseJSONPolygon(keys *parseKeys, opts *ParseOptions) (Object, error) {
 		exterior[3].X == exterior[4].X &&
 		exterior[3].Y > exterior[4].Y {
 		// simple rectangle
-		o = NewRect(geometry.Rect{
+		return NewRect(geometry.Rect{
 			Min: exterior[0],
 			Max: exterior[2],
 		})
-	} else {
-		g := Polygon{}
-		poly := geometry.NewPoly(exterior, holes, &gopts)
-		g.base = *poly
-		g.extra = extra
-		o = &g
 	}
-	if opts.RequireValid {
-		if !o.Valid() {
-			return nil, errCoordinatesInvalid
-		}
-	}
-	return o, nil
+	gopts := toGeometryOpts(opts)
+	poly := geometry.NewPoly(exterior, holes, &gopts)
+	return &Polygon{base: *poly, extra: extra}
 }
 
 func parseJSONPolygonCoords(
@@ -264,19 +286,19 @@ func (g *Polygon) Distance(obj Object) float64 {
 }
 
 func (g *Polygon) DistancePoint(point geometry.Point) float64 {
-	return geoDistancePoints(g.Center(), point)
+	return geoDistancePointPoly(point, &g.base)
 }
 
 func (g *Polygon) DistanceRect(rect geometry.Rect) float64 {
-	return geoDistancePoints(g.Center(), rect.Center())
+	return geoDistancePolyRect(&g.base, rect)
 }
 
 func (g *Polygon) DistanceLine(line *geometry.Line) float64 {
-	return geoDistancePoints(g.Center(), line.Rect().Center())
+	return geoDistanceLinePoly(line, &g.base)
 }
 
 func (g *Polygon) DistancePoly(poly *geometry.Poly) float64 {
-	return geoDistancePoints(g.Center(), poly.Rect().Center())
+	return geoDistancePolyPoly(&g.base, poly)
 }
 
 func (g *Polygon) HasExtra() bool {
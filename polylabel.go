@@ -0,0 +1,33 @@
+package geojson
+
+import "github.com/tidwall/geojson/geometry"
+
+// Polylabel returns the pole of inaccessibility of the polygon, which is the
+// point inside of the polygon that is farthest from its edges, and a good
+// place for a label. The precision is in degrees, and a precision of zero or
+// less uses one thousandth of the larger side of the polygon rectangle.
+func (g *Polygon) Polylabel(precision float64) geometry.Point {
+	point, _ := g.base.Polylabel(precision)
+	return point
+}
+
+// Polylabel returns the pole of inaccessibility of the polygon that has the
+// point farthest from its edges. See Polygon.Polylabel.
+func (g *MultiPolygon) Polylabel(precision float64) geometry.Point {
+	var best geometry.Point
+	bestDist := -1.0
+	for _, child := range g.children {
+		poly, ok := child.(*Polygon)
+		if !ok || poly.Empty() {
+			continue
+		}
+		point, dist := poly.base.Polylabel(precision)
+		if dist > bestDist {
+			best, bestDist = point, dist
+		}
+	}
+	if bestDist < 0 {
+		return g.Center()
+	}
+	return best
+}
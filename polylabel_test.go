@@ -0,0 +1,26 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolylabel(t *testing.T) {
+	lshape := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,2],[2,2],[2,10],[0,10],[0,0]]
+	]}`, nil).(*Polygon)
+	point := lshape.Polylabel(0.001)
+	expect(t, lshape.Contains(NewPoint(point)))
+	expect(t, point.X < 2 && point.Y < 2)
+
+	multi := expectJSON(t, `{"type":"MultiPolygon","coordinates":[
+		[[[0,0],[1,0],[1,1],[0,1],[0,0]]],
+		[[[10,10],[20,10],[20,20],[10,20],[10,10]]]
+	]}`, nil).(*MultiPolygon)
+	point = multi.Polylabel(0.01)
+	expect(t, math.Abs(point.X-15) <= 0.01 && math.Abs(point.Y-15) <= 0.01)
+
+	empty := expectJSON(t, `{"type":"MultiPolygon","coordinates":[]}`,
+		nil).(*MultiPolygon)
+	expect(t, empty.Polylabel(1) == empty.Center())
+}
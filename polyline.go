@@ -0,0 +1,99 @@
+package geojson
+
+import "github.com/tidwall/geojson/geometry"
+
+// PolylineOptions are the options for a Google encoded polyline.
+type PolylineOptions struct {
+	// Precision is the number of decimal places of the coordinates, which is
+	// 5 for Google and 6 for some other routing services.
+	// The default is 5.
+	Precision int
+	// Z option will cause each point to have a third value, which is the Z
+	// coordinate of the LineString.
+	Z bool
+}
+
+var DefaultPolylineOptions = &PolylineOptions{
+	Precision: 5,
+}
+
+func polylineDims(popts *PolylineOptions) (precision, dims int) {
+	if popts == nil {
+		popts = DefaultPolylineOptions
+	}
+	precision, dims = popts.Precision, 2
+	if precision == 0 {
+		precision = 5
+	}
+	if popts.Z {
+		dims = 3
+	}
+	return precision, dims
+}
+
+// ParsePolyline returns a LineString from a Google encoded polyline. An
+// empty polyline returns an empty LineString.
+func ParsePolyline(polyline string, popts *PolylineOptions, opts *ParseOptions,
+) (*LineString, error) {
+	if opts == nil {
+		opts = DefaultParseOptions
+	}
+	precision, dims := polylineDims(popts)
+	values, ok := geometry.DecodePolyline(polyline, precision, dims)
+	if !ok {
+		return nil, errCoordinatesInvalid
+	}
+	n := len(values) / dims
+	if n == 1 {
+		return nil, errCoordinatesInvalid
+	}
+	points := make([]geometry.Point, n)
+	var ex *extra
+	if dims == 3 {
+		ex = &extra{dims: 1, values: make([]float64, n)}
+	}
+	for i := range points {
+		points[i] = geometry.Point{X: values[i*dims+1], Y: values[i*dims]}
+		if ex != nil {
+			ex.values[i] = values[i*dims+2]
+		}
+	}
+	gopts := toGeometryOpts(opts)
+	g := &LineString{base: *geometry.NewLine(points, &gopts), extra: ex}
+	if opts.RequireValid {
+		if !g.Valid() {
+			return nil, errDataInvalid
+		}
+	}
+	return g, nil
+}
+
+// AppendPolyline appends the LineString as a Google encoded polyline. With
+// the Z option, a LineString without Z coordinates has a Z of zero.
+func (g *LineString) AppendPolyline(dst []byte, popts *PolylineOptions,
+) []byte {
+	precision, dims := polylineDims(popts)
+	if dims == 2 {
+		return g.base.AppendPolyline(dst, precision)
+	}
+	var zdims int
+	if g.extra != nil && !(g.extra.dims == 1 && g.extra.measure) {
+		zdims = int(g.extra.dims)
+	}
+	n := g.base.NumPoints()
+	values := make([]float64, 0, n*dims)
+	for i := 0; i < n; i++ {
+		point := g.base.PointAt(i)
+		var z float64
+		if zdims > 0 {
+			z = g.extra.values[i*zdims]
+		}
+		values = append(values, point.Y, point.X, z)
+	}
+	return geometry.AppendPolyline(dst, values, precision, dims)
+}
+
+// Polyline returns the LineString as a Google encoded polyline.
+func (g *LineString) Polyline(popts *PolylineOptions) string {
+	return string(g.AppendPolyline(nil, popts))
+}
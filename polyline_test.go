@@ -0,0 +1,38 @@
+package geojson
+
+import "testing"
+
+func TestPolyline(t *testing.T) {
+	g, err := ParsePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@", nil, nil)
+	expect(t, err == nil)
+	expect(t, g.JSON() == `{"type":"LineString","coordinates":`+
+		`[[-120.2,38.5],[-120.95,40.7],[-126.453,43.252]]}`)
+	expect(t, g.Polyline(nil) == "_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	expect(t, g.Intersects(PO(-120.2, 38.5)))
+
+	// Z coordinates
+	zopts := &PolylineOptions{Precision: 6, Z: true}
+	g = expectJSON(t, `{"type":"LineString","coordinates":`+
+		`[[-120.2,38.5,10.25],[-120.95,40.7,-3]]}`, nil).(*LineString)
+	polyline := g.Polyline(zopts)
+	g2, err := ParsePolyline(polyline, zopts, nil)
+	expect(t, err == nil)
+	expect(t, g2.JSON() == g.JSON())
+	g2, err = ParsePolyline(g.Polyline(nil), nil, nil)
+	expect(t, err == nil)
+	expect(t, g2.JSON() == `{"type":"LineString","coordinates":`+
+		`[[-120.2,38.5],[-120.95,40.7]]}`)
+	g = expectJSON(t, `{"type":"LineString","coordinates":`+
+		`[[1,2],[3,4]]}`, nil).(*LineString)
+	g2, err = ParsePolyline(g.Polyline(zopts), zopts, nil)
+	expect(t, err == nil)
+	expect(t, g2.JSON() == `{"type":"LineString","coordinates":`+
+		`[[1,2,0],[3,4,0]]}`)
+
+	g, err = ParsePolyline("", nil, nil)
+	expect(t, err == nil && g.Empty())
+	_, err = ParsePolyline("_p~iF~ps|U", nil, nil)
+	expect(t, err == errCoordinatesInvalid)
+	_, err = ParsePolyline("_p~iF~ps|U_", nil, nil)
+	expect(t, err == errCoordinatesInvalid)
+}
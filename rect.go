@@ -59,7 +59,24 @@ func (g *Rect) String() string {
 	return string(g.AppendJSON(nil))
 }
 
+// AppendWKT appends the Rect as a well-known text polygon
+func (g *Rect) AppendWKT(dst []byte) []byte {
+	return appendWKTObject(dst, "POLYGON", g, nil)
+}
+
+func (g *Rect) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+// AppendWKB appends the Rect as a well-known binary polygon
+func (g *Rect) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	return appendWKBObject(dst, opts, g, 0, false)
+}
+
 func (g *Rect) Contains(obj Object) bool {
+	if obj, ok := obj.(*Circle); ok {
+		return obj.withinPoly(&geometry.Poly{Exterior: g.base})
+	}
 	return obj.Spatial().WithinRect(g.base)
 }
 
@@ -84,6 +101,9 @@ func (g *Rect) WithinPoly(poly *geometry.Poly) bool {
 }
 
 func (g *Rect) Intersects(obj Object) bool {
+	if obj, ok := obj.(*Circle); ok {
+		return obj.Intersects(g)
+	}
 	return obj.Spatial().IntersectsRect(g.base)
 }
 
@@ -116,19 +136,19 @@ func (g *Rect) Distance(obj Object) float64 {
 }
 
 func (g *Rect) DistancePoint(point geometry.Point) float64 {
-	return geoDistancePoints(g.Center(), point)
+	return geoDistancePointRect(point, g.base)
 }
 
 func (g *Rect) DistanceRect(rect geometry.Rect) float64 {
-	return geoDistancePoints(g.Center(), rect.Center())
+	return geoDistanceRectRect(g.base, rect)
 }
 
 func (g *Rect) DistanceLine(line *geometry.Line) float64 {
-	return geoDistancePoints(g.Center(), line.Rect().Center())
+	return geoDistanceLineRect(line, g.base)
 }
 
 func (g *Rect) DistancePoly(poly *geometry.Poly) float64 {
-	return geoDistancePoints(g.Center(), poly.Rect().Center())
+	return geoDistancePolyRect(poly, g.base)
 }
 
 func (g *Rect) Members() string {
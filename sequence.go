@@ -0,0 +1,135 @@
+package geojson
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/tidwall/gjson"
+)
+
+// SequenceFormat is the format of a sequence of GeoJSON objects.
+type SequenceFormat byte
+
+// SequenceFormat types
+const (
+	// TextSequence is a GeoJSON text sequence (RFC 8142), where each object
+	// starts with a record separator (0x1E) and ends with a line feed.
+	TextSequence SequenceFormat = iota
+	// NDJSON is newline-delimited GeoJSON, where each object is on its own
+	// line.
+	NDJSON
+)
+
+const recordSeparator = 0x1E
+
+// SequenceReader reads the GeoJSON objects of a sequence from a stream.
+type SequenceReader struct {
+	rd      *bufio.Reader
+	format  SequenceFormat
+	opts    *ParseOptions
+	index   int
+	skipped int
+	started bool
+	err     error
+}
+
+// NewSequenceReader returns a reader for a sequence of objects from r. Each
+// object is parsed using the provided options.
+func NewSequenceReader(r io.Reader, format SequenceFormat, opts *ParseOptions,
+) *SequenceReader {
+	if opts == nil {
+		opts = DefaultParseOptions
+	}
+	return &SequenceReader{rd: bufio.NewReader(r), format: format, opts: opts}
+}
+
+// Skipped returns the number of records of a text sequence that were skipped
+// because they were truncated, or otherwise not json. RFC 8142 requires that
+// these are ignored.
+func (s *SequenceReader) Skipped() int {
+	return s.skipped
+}
+
+// Next returns the next object of the sequence. It returns io.EOF when there
+// are no more objects. An object that is not valid, or a line of NDJSON that
+// is not json, returns a *DecodeError with the index of its record, and the
+// reader can continue with the next record. Other errors stop the reader.
+func (s *SequenceReader) Next() (Object, error) {
+	delim := byte('\n')
+	if s.format == TextSequence {
+		delim = recordSeparator
+	}
+	for s.err == nil {
+		record, err := s.rd.ReadBytes(delim)
+		if err != nil && err != io.EOF {
+			s.err = err
+			break
+		}
+		if err == io.EOF {
+			s.err = io.EOF
+		}
+		if len(record) > 0 && record[len(record)-1] == delim {
+			record = record[:len(record)-1]
+		}
+		started := s.started
+		s.started = true
+		if s.format == TextSequence && !started {
+			// the text before the first record separator is not a record
+			if len(bytes.TrimSpace(record)) > 0 {
+				s.skipped++
+			}
+			continue
+		}
+		record = bytes.TrimSpace(record)
+		if len(record) == 0 {
+			continue
+		}
+		if s.format == TextSequence && !gjson.ValidBytes(record) {
+			s.skipped++
+			continue
+		}
+		index := s.index
+		s.index++
+		if !gjson.ValidBytes(record) {
+			// a line that is not json, such as well-known text
+			return nil, &DecodeError{Index: index, Err: errDataInvalid,
+				kind: "record"}
+		}
+		obj, err := Parse(string(record), s.opts)
+		if err != nil {
+			return nil, &DecodeError{Index: index, Err: err, kind: "record"}
+		}
+		return obj, nil
+	}
+	return nil, s.err
+}
+
+// SequenceWriter writes GeoJSON objects as a sequence to a stream.
+type SequenceWriter struct {
+	w      io.Writer
+	format SequenceFormat
+	buf    []byte
+	err    error
+}
+
+// NewSequenceWriter returns a writer for a sequence of objects to w.
+func NewSequenceWriter(w io.Writer, format SequenceFormat) *SequenceWriter {
+	return &SequenceWriter{w: w, format: format}
+}
+
+// Write writes an object as the next record of the sequence. Each record is
+// written with a single call to the stream.
+func (s *SequenceWriter) Write(obj Object) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.buf = s.buf[:0]
+	if s.format == TextSequence {
+		s.buf = append(s.buf, recordSeparator)
+	}
+	s.buf = obj.AppendJSON(s.buf)
+	s.buf = append(s.buf, '\n')
+	_, s.err = s.w.Write(s.buf)
+	return s.err
+}
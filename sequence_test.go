@@ -0,0 +1,113 @@
+package geojson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readSequence(t *testing.T, s *SequenceReader) ([]string, []int) {
+	t.Helper()
+	var jsons []string
+	var errs []int
+	for {
+		obj, err := s.Next()
+		if err == io.EOF {
+			return jsons, errs
+		}
+		var derr *DecodeError
+		if errors.As(err, &derr) {
+			errs = append(errs, derr.Index)
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsons = append(jsons, obj.JSON())
+	}
+}
+
+func TestSequenceTextSequence(t *testing.T) {
+	p1 := `{"type":"Point","coordinates":[1,2]}`
+	p2 := `{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4]},"properties":{}}`
+	data := "\x1e" + p1 + "\n" +
+		"\x1e" + `{"type":"Point","coordi` + // truncated
+		"\x1e" + "\n" + // empty
+		"\x1e" + `{"type":"Point"}` + "\n" + // not valid geojson
+		"\x1e" + "  " + p2 + "\n" +
+		"\x1e" + `12` // not geojson
+	s := NewSequenceReader(strings.NewReader(data), TextSequence, nil)
+	jsons, errs := readSequence(t, s)
+	expect(t, len(jsons) == 2 && jsons[0] == p1 && jsons[1] == p2)
+	expect(t, len(errs) == 2 && errs[0] == 1 && errs[1] == 3)
+	expect(t, s.Skipped() == 1)
+	_, err := s.Next()
+	expect(t, err == io.EOF)
+
+	// text before the first record separator
+	s = NewSequenceReader(strings.NewReader(p1+"\n\x1e"+p1+"\n"),
+		TextSequence, nil)
+	jsons, _ = readSequence(t, s)
+	expect(t, len(jsons) == 1 && s.Skipped() == 1)
+
+	var buf bytes.Buffer
+	w := NewSequenceWriter(&buf, TextSequence)
+	expect(t, w.Write(PO(1, 2)) == nil)
+	expect(t, w.Write(expectJSON(t, p2, nil)) == nil)
+	expect(t, buf.String() == "\x1e"+p1+"\n\x1e"+p2+"\n")
+}
+
+func TestSequenceNDJSON(t *testing.T) {
+	p1 := `{"type":"Point","coordinates":[1,2]}`
+	data := p1 + "\n\n" + `{"type":"Point","coordinates":[1,]}` + "\r\n" +
+		"  " + p1 + "  \n" + p1
+	opts := *DefaultParseOptions
+	opts.AllowSimplePoints = true
+	s := NewSequenceReader(strings.NewReader(data), NDJSON, &opts)
+	obj, err := s.Next()
+	expect(t, err == nil)
+	_, ok := obj.(*SimplePoint)
+	expect(t, ok)
+	_, err = s.Next()
+	var derr *DecodeError
+	expect(t, errors.As(err, &derr) && derr.Index == 1 &&
+		derr.Err == errDataInvalid)
+	expect(t, err.Error() == "record 1: "+errDataInvalid.Error())
+	jsons, errs := readSequence(t, s)
+	expect(t, len(jsons) == 2 && len(errs) == 0)
+
+	// a line that is not json is not parsed as well-known text
+	s = NewSequenceReader(strings.NewReader("POINT (1 2)\n"+p1), NDJSON, nil)
+	jsons, errs = readSequence(t, s)
+	expect(t, len(jsons) == 1 && len(errs) == 1 && errs[0] == 0)
+	s = NewSequenceReader(strings.NewReader("POINT (1 2)"), NDJSON, nil)
+	_, err = s.Next()
+	expect(t, errors.As(err, &derr) && derr.Index == 0 &&
+		derr.Err == errDataInvalid)
+	_, err = s.Next()
+	expect(t, err == io.EOF)
+
+	var buf bytes.Buffer
+	w := NewSequenceWriter(&buf, NDJSON)
+	for i := 0; i < 3; i++ {
+		expect(t, w.Write(PO(1, 2)) == nil)
+	}
+	expect(t, buf.String() == strings.Repeat(p1+"\n", 3))
+	s = NewSequenceReader(&buf, NDJSON, nil)
+	jsons, _ = readSequence(t, s)
+	expect(t, len(jsons) == 3)
+
+	// errors are kept
+	werr := errors.New("write error")
+	ew := &errWriter{err: werr}
+	w = NewSequenceWriter(ew, NDJSON)
+	expect(t, w.Write(PO(1, 2)) == werr)
+	expect(t, w.Write(PO(1, 2)) == werr)
+	expect(t, ew.n == 1)
+	rerr := errors.New("read error")
+	s = NewSequenceReader(errReader{rerr}, NDJSON, nil)
+	_, err = s.Next()
+	expect(t, err == rerr)
+}
@@ -58,6 +58,19 @@ func (g *SimplePoint) String() string {
 	return string(g.AppendJSON(nil))
 }
 
+func (g *SimplePoint) AppendWKT(dst []byte) []byte {
+	return appendWKTObject(dst, "POINT", g, nil)
+}
+
+func (g *SimplePoint) WKT() string {
+	return string(g.AppendWKT(nil))
+}
+
+func (g *SimplePoint) AppendWKB(dst []byte, opts *WKBOptions) []byte {
+	dims, measure := extraDims(nil)
+	return appendWKBObject(dst, opts, g, dims, measure)
+}
+
 func (g *SimplePoint) Within(obj Object) bool {
 	return obj.Contains(g)
 }
@@ -118,15 +131,15 @@ func (g *SimplePoint) DistancePoint(point geometry.Point) float64 {
 }
 
 func (g *SimplePoint) DistanceRect(rect geometry.Rect) float64 {
-	return geoDistancePoints(g.Center(), rect.Center())
+	return geoDistancePointRect(g.Point, rect)
 }
 
 func (g *SimplePoint) DistanceLine(line *geometry.Line) float64 {
-	return geoDistancePoints(g.Center(), line.Rect().Center())
+	return geoDistancePointLine(g.Point, line)
 }
 
 func (g *SimplePoint) DistancePoly(poly *geometry.Poly) float64 {
-	return geoDistancePoints(g.Center(), poly.Rect().Center())
+	return geoDistancePointPoly(g.Point, poly)
 }
 
 func (g *SimplePoint) Members() string {
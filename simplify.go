@@ -0,0 +1,428 @@
+package geojson
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// SimplifyAlgorithm is the algorithm used to simplify geometries.
+type SimplifyAlgorithm byte
+
+// SimplifyAlgorithm types
+const (
+	// DouglasPeucker removes the points that are within the tolerance of the
+	// simplified line.
+	DouglasPeucker SimplifyAlgorithm = iota
+	// Visvalingam removes the points that form the smallest triangles with
+	// their neighbors.
+	Visvalingam
+)
+
+// SimplifyOptions are the options for Simplify.
+type SimplifyOptions struct {
+	// Algorithm is the simplification algorithm. The default is
+	// DouglasPeucker.
+	Algorithm SimplifyAlgorithm
+	// Tolerance is the largest distance that a removed point may be from the
+	// simplified line. For Visvalingam, points are removed while the area of
+	// the triangle that they form with their neighbors is smaller than the
+	// tolerance squared.
+	Tolerance float64
+	// Meters causes the tolerance to be in meters rather than degrees.
+	Meters bool
+	// PreserveTopology keeps the polygons valid. Rings keep at least four
+	// points and do not cross themselves or each other, and holes stay inside
+	// of their exteriors. Without it, rings that collapse are removed.
+	PreserveTopology bool
+}
+
+// maxSimplifyPasses is the number of times that the rings with topology
+// errors are simplified with a smaller tolerance before their original points
+// are used.
+const maxSimplifyPasses = 8
+
+// Simplify returns a simplified LineString, Polygon, MultiLineString,
+// MultiPolygon, or Feature of those, with fewer points. The extra Z and M
+// coordinate values of the retained points are kept. Other objects are
+// returned as is.
+func Simplify(obj Object, opts *SimplifyOptions) Object {
+	if opts == nil {
+		opts = &SimplifyOptions{}
+	}
+	switch g := obj.(type) {
+	case *LineString:
+		return simplifyLineString(g, opts)
+	case *MultiLineString:
+		ng := new(MultiLineString)
+		ng.extra = g.extra
+		for _, child := range g.children {
+			ng.children = append(ng.children, Simplify(child, opts))
+		}
+		ng.parseInitRectIndex(DefaultParseOptions)
+		return ng
+	case *Polygon:
+		return simplifyPolygons([]*Polygon{g}, opts)[0]
+	case *MultiPolygon:
+		var polys []*Polygon
+		for _, child := range g.children {
+			if poly, ok := child.(*Polygon); ok {
+				polys = append(polys, poly)
+			}
+		}
+		ng := new(MultiPolygon)
+		ng.extra = g.extra
+		for _, poly := range simplifyPolygons(polys, opts) {
+			if !poly.Empty() {
+				ng.children = append(ng.children, poly)
+			}
+		}
+		ng.parseInitRectIndex(DefaultParseOptions)
+		return ng
+	case *Feature:
+		return NewFeature(Simplify(g.base, opts), g.Members())
+	}
+	return obj
+}
+
+func simplifyLineString(g *LineString, opts *SimplifyOptions) *LineString {
+	points := seriesPoints(&g.base)
+	keep := simplifyPoints(points, opts, opts.Tolerance, 2)
+	ng := NewLineString(geometry.NewLine(keptPoints(points, keep), nil))
+	ng.extra = keptExtra(g.extra, [][]int{keep}, []int{len(points)})
+	return ng
+}
+
+// simplifyPolygons simplifies polygons together, which is needed to keep the
+// polygons of a MultiPolygon from crossing each other.
+func simplifyPolygons(polys []*Polygon, opts *SimplifyOptions) []*Polygon {
+	// gather the rings of all polygons
+	var rings [][]geometry.Point
+	var owners []int // polygon index for each ring
+	var holes []bool
+	for i, g := range polys {
+		if g.base.Exterior == nil {
+			continue
+		}
+		for j, ring := range append([]geometry.Ring{g.base.Exterior},
+			g.base.Holes...) {
+			rings = append(rings, seriesPoints(ring))
+			owners = append(owners, i)
+			holes = append(holes, j > 0)
+		}
+	}
+	keeps := make([][]int, len(rings))
+	minPoints := 0
+	if opts.PreserveTopology {
+		minPoints = 4
+	}
+	tolerances := make([]float64, len(rings))
+	for i := range rings {
+		tolerances[i] = opts.Tolerance
+		keeps[i] = simplifyPoints(rings[i], opts, tolerances[i], minPoints)
+	}
+	if opts.PreserveTopology {
+		for pass := 0; ; pass++ {
+			conflicts := ringConflicts(rings, keeps, owners, holes)
+			if len(conflicts) == 0 {
+				break
+			}
+			for i := range conflicts {
+				if pass == maxSimplifyPasses {
+					keeps[i] = nil
+				} else {
+					tolerances[i] /= 4
+					keeps[i] = simplifyPoints(rings[i], opts, tolerances[i],
+						minPoints)
+				}
+			}
+			if pass == maxSimplifyPasses {
+				break
+			}
+		}
+	}
+	// rebuild the polygons
+	result := make([]*Polygon, len(polys))
+	for i, g := range polys {
+		var exterior []geometry.Point
+		var inner [][]geometry.Point
+		var ringKeeps [][]int
+		var ringSizes []int
+		for j := range rings {
+			if owners[j] != i {
+				continue
+			}
+			ringKeeps = append(ringKeeps, keeps[j])
+			ringSizes = append(ringSizes, len(rings[j]))
+			points := keptPoints(rings[j], keeps[j])
+			if len(points) < 4 {
+				if holes[j] {
+					// collapsed hole
+					ringKeeps[len(ringKeeps)-1] = []int{}
+					continue
+				}
+				// collapsed exterior
+				exterior = nil
+				break
+			}
+			if holes[j] {
+				inner = append(inner, points)
+			} else {
+				exterior = points
+			}
+		}
+		if exterior == nil {
+			result[i] = NewPolygon(nil)
+			continue
+		}
+		result[i] = &Polygon{
+			base:  *geometry.NewPoly(exterior, inner, nil),
+			extra: keptExtra(g.extra, ringKeeps, ringSizes),
+		}
+	}
+	return result
+}
+
+// ringConflicts returns the rings, as simplified by their kept points, that
+// cross themselves or another ring, and the holes that are not inside of
+// their exterior.
+func ringConflicts(rings [][]geometry.Point, keeps [][]int, owners []int,
+	holes []bool,
+) map[int]bool {
+	conflicts := make(map[int]bool)
+	lines := make([]*geometry.Line, len(rings))
+	for i := range rings {
+		lines[i] = geometry.NewLine(keptPoints(rings[i], keeps[i]), nil)
+	}
+	for i, line := range lines {
+		n := line.NumSegments()
+		for j := 0; j < n; j++ {
+			seg := line.SegmentAt(j)
+			for k, other := range lines {
+				if k < i || !other.Rect().IntersectsRect(seg.Rect()) {
+					continue
+				}
+				m := other.NumSegments()
+				other.Search(seg.Rect(), func(oseg geometry.Segment, idx int) bool {
+					if k == i && (idx == j || idx == j+1 || idx == j-1 ||
+						(j == 0 && idx == m-1) || (idx == 0 && j == m-1)) {
+						// neighboring segments of the same ring
+						return true
+					}
+					if seg.IntersectsSegment(oseg) {
+						conflicts[i] = true
+						conflicts[k] = true
+						return false
+					}
+					return true
+				})
+			}
+		}
+	}
+	// holes must be inside of their exterior
+	for i := range lines {
+		if !holes[i] || lines[i].NumPoints() == 0 {
+			continue
+		}
+		for j := range lines {
+			if holes[j] || owners[j] != owners[i] {
+				continue
+			}
+			exterior := &geometry.Poly{Exterior: lines[j]}
+			if !exterior.ContainsPoint(lines[i].PointAt(0)) {
+				conflicts[i] = true
+				conflicts[j] = true
+			}
+		}
+	}
+	return conflicts
+}
+
+// simplifyPoints returns the indexes of the points that remain. The result
+// has at least minPoints points, when possible. A nil result means all
+// points.
+func simplifyPoints(points []geometry.Point, opts *SimplifyOptions,
+	tolerance float64, minPoints int,
+) []int {
+	if len(points) <= 2 || len(points) <= minPoints {
+		return nil
+	}
+	proj := points
+	if opts.Meters {
+		proj = projectMeters(points)
+	}
+	for i := 0; i < maxSimplifyPasses; i++ {
+		var keep []int
+		if opts.Algorithm == Visvalingam {
+			keep = visvalingam(proj, tolerance*tolerance)
+		} else {
+			keep = douglasPeucker(proj, tolerance)
+		}
+		if len(keep) >= minPoints {
+			return keep
+		}
+		tolerance /= 4
+	}
+	return nil
+}
+
+// projectMeters projects the points onto a plane in meters, which is centered
+// at the middle of the points.
+func projectMeters(points []geometry.Point) []geometry.Point {
+	rect := geometry.Rect{Min: points[0], Max: points[0]}
+	for _, point := range points[1:] {
+		rect = unionRects(rect, point.Rect())
+	}
+	plane := newPlaneProjection(rect)
+	proj := make([]geometry.Point, len(points))
+	for i, point := range points {
+		proj[i] = plane.project(point)
+	}
+	return proj
+}
+
+func douglasPeucker(points []geometry.Point, tolerance float64) []int {
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		span := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		seg := geometry.Segment{A: points[span[0]], B: points[span[1]]}
+		index, dist := -1, tolerance
+		for i := span[0] + 1; i < span[1]; i++ {
+			if d := seg.DistancePoint(points[i]); d > dist {
+				index, dist = i, d
+			}
+		}
+		if index != -1 {
+			keep[index] = true
+			stack = append(stack, [2]int{span[0], index}, [2]int{index, span[1]})
+		}
+	}
+	var indexes []int
+	for i, ok := range keep {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func triangleArea(a, b, c geometry.Point) float64 {
+	return math.Abs(geometry.Orient(a, b, c)) / 2
+}
+
+// vwPoint is a point in the Visvalingam queue.
+type vwPoint struct {
+	index      int
+	area       float64
+	prev, next int
+	heapIndex  int
+}
+
+type vwQueue []*vwPoint
+
+func (q vwQueue) Len() int           { return len(q) }
+func (q vwQueue) Less(i, j int) bool { return q[i].area < q[j].area }
+func (q vwQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].heapIndex = i
+	q[j].heapIndex = j
+}
+func (q *vwQueue) Push(x interface{}) {
+	p := x.(*vwPoint)
+	p.heapIndex = len(*q)
+	*q = append(*q, p)
+}
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	p := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return p
+}
+
+func visvalingam(points []geometry.Point, minArea float64) []int {
+	n := len(points)
+	vps := make([]vwPoint, n)
+	queue := make(vwQueue, 0, n)
+	for i := range vps {
+		vps[i] = vwPoint{index: i, prev: i - 1, next: i + 1}
+		if i > 0 && i < n-1 {
+			vps[i].area = triangleArea(points[i-1], points[i], points[i+1])
+			heap.Push(&queue, &vps[i])
+		}
+	}
+	removed := make([]bool, n)
+	var last float64
+	for queue.Len() > 0 {
+		p := heap.Pop(&queue).(*vwPoint)
+		if p.area >= minArea {
+			break
+		}
+		// the effective area never decreases, so that a point is not
+		// removed before the points that were removed to get to it.
+		last = math.Max(last, p.area)
+		removed[p.index] = true
+		vps[p.prev].next = p.next
+		vps[p.next].prev = p.prev
+		for _, i := range [...]int{p.prev, p.next} {
+			q := &vps[i]
+			if i == 0 || i == n-1 {
+				continue
+			}
+			q.area = math.Max(last, triangleArea(points[q.prev], points[i],
+				points[q.next]))
+			heap.Fix(&queue, q.heapIndex)
+		}
+	}
+	var indexes []int
+	for i := range points {
+		if !removed[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func seriesPoints(series geometry.Series) []geometry.Point {
+	return appendSeriesPoints(nil, series)
+}
+
+// keptPoints returns the points at the kept indexes, or all points when keep
+// is nil.
+func keptPoints(points []geometry.Point, keep []int) []geometry.Point {
+	if keep == nil {
+		return points
+	}
+	kept := make([]geometry.Point, len(keep))
+	for i, index := range keep {
+		kept[i] = points[index]
+	}
+	return kept
+}
+
+// keptExtra returns the extra with only the coordinate values of the kept
+// points of each series, in order.
+func keptExtra(ex *extra, keeps [][]int, sizes []int) *extra {
+	if ex == nil || ex.dims == 0 {
+		return ex
+	}
+	dims := int(ex.dims)
+	nex := &extra{dims: ex.dims, measure: ex.measure, members: ex.members}
+	var pidx int
+	for i, keep := range keeps {
+		if keep == nil {
+			nex.values = append(nex.values,
+				ex.values[pidx*dims:(pidx+sizes[i])*dims]...)
+		} else {
+			for _, index := range keep {
+				j := (pidx + index) * dims
+				nex.values = append(nex.values, ex.values[j:j+dims]...)
+			}
+		}
+		pidx += sizes[i]
+	}
+	return nex
+}
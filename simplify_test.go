@@ -0,0 +1,131 @@
+package geojson
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestSimplifyLineString(t *testing.T) {
+	g := expectJSON(t, `{"type":"LineString","coordinates":[[0,0,1],[1,0.05,2],[2,0,3],[3,0.05,4],[4,0,5]]}`, nil)
+	expectJSON(t, Simplify(g, &SimplifyOptions{Tolerance: 0.1}).JSON(),
+		`{"type":"LineString","coordinates":[[0,0,1],[4,0,5]]}`)
+	expect(t, Simplify(g, &SimplifyOptions{Tolerance: 0.01}).JSON() == g.JSON())
+	expect(t, Simplify(g, nil).JSON() == g.JSON())
+	// meters, where 0.05 degrees is about 5.5 km
+	expect(t, Simplify(g, &SimplifyOptions{
+		Tolerance: 1000, Meters: true,
+	}).NumPoints() == 5)
+	expect(t, Simplify(g, &SimplifyOptions{
+		Tolerance: 10000, Meters: true,
+	}).NumPoints() == 2)
+	// visvalingam, where each triangle has an area of 0.05
+	expectJSON(t, Simplify(g, &SimplifyOptions{
+		Algorithm: Visvalingam, Tolerance: 0.5,
+	}).JSON(), `{"type":"LineString","coordinates":[[0,0,1],[4,0,5]]}`)
+	expect(t, Simplify(g, &SimplifyOptions{
+		Algorithm: Visvalingam, Tolerance: 0.1,
+	}).NumPoints() == 5)
+	g = expectJSON(t, `{"type":"MultiLineString","coordinates":[
+		[[0,0],[1,0.05],[2,0]],[[0,1],[1,1.5],[2,1]]
+	],"id":1}`, nil)
+	expectJSON(t, Simplify(g, &SimplifyOptions{Tolerance: 0.1}).JSON(),
+		`{"type":"MultiLineString","coordinates":[[[0,0],[2,0]],[[0,1],[1,1.5],[2,1]]],"id":1}`)
+}
+
+func TestSimplifyPolygon(t *testing.T) {
+	g := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0,1],[5,0.1,2],[10,0,3],[10,10,4],[0,10,5],[0,0,1]],
+		[[4,4,6],[4.1,4,7],[4.1,4.1,8],[4,4,6]]
+	]}`, nil)
+	expectJSON(t, Simplify(g, &SimplifyOptions{Tolerance: 0.5}).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0,1],[10,0,3],[10,10,4],[0,10,5],[0,0,1]]]}`)
+	// the hole is kept
+	expectJSON(t, Simplify(g, &SimplifyOptions{
+		Tolerance: 0.5, PreserveTopology: true,
+	}).JSON(),
+		`{"type":"Polygon","coordinates":[[[0,0,1],[10,0,3],[10,10,4],[0,10,5],[0,0,1]],[[4,4,6],[4.1,4,7],[4.1,4.1,8],[4,4,6]]]}`)
+	// the whole polygon collapses
+	expect(t, Simplify(g, &SimplifyOptions{Tolerance: 20}).Empty())
+	expect(t, !Simplify(g, &SimplifyOptions{
+		Tolerance: 20, PreserveTopology: true,
+	}).Empty())
+}
+
+func TestSimplifyTopology(t *testing.T) {
+	// the hole is inside of a bump, which is removed without topology
+	g := expectJSON(t, `{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,10],[6,10],[5,12],[4,10],[0,10],[0,0]],
+		[[4.8,10.5],[5.2,10.5],[5.2,11],[4.8,10.5]]
+	]}`, nil)
+	inHole := P(5, 10.6)
+	simple := Simplify(g, &SimplifyOptions{Tolerance: 3}).(*Polygon)
+	expect(t, simple.base.Exterior.NumPoints() == 5)
+	exterior := &geometry.Poly{Exterior: simple.base.Exterior}
+	expect(t, !exterior.ContainsPoint(inHole))
+	simple = Simplify(g, &SimplifyOptions{
+		Tolerance: 3, PreserveTopology: true,
+	}).(*Polygon)
+	expect(t, len(simple.base.Holes) == 1)
+	exterior = &geometry.Poly{Exterior: simple.base.Exterior}
+	expect(t, exterior.ContainsPoint(inHole))
+
+	// the rings of a multipolygon do not cross
+	g = expectJSON(t, `{"type":"MultiPolygon","coordinates":[
+		[[[0,0],[10,0],[10,10],[5,9],[0,10],[0,0]]],
+		[[[5,9.5],[10,15],[0,15],[5,9.5]]]
+	]}`, nil)
+	mp := Simplify(g, &SimplifyOptions{Tolerance: 2}).(*MultiPolygon)
+	a := mp.children[0].(*Polygon)
+	b := mp.children[1].(*Polygon)
+	expect(t, a.base.IntersectsPoly(&b.base))
+	mp = Simplify(g, &SimplifyOptions{
+		Tolerance: 2, PreserveTopology: true,
+	}).(*MultiPolygon)
+	a = mp.children[0].(*Polygon)
+	b = mp.children[1].(*Polygon)
+	expect(t, !a.base.IntersectsPoly(&b.base))
+}
+
+func TestSimplifyFeature(t *testing.T) {
+	g := expectJSON(t, `{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[1,0.05],[2,0]]},"id":1,"properties":{"a":1}}`, nil)
+	expectJSON(t, Simplify(g, &SimplifyOptions{Tolerance: 0.1}).JSON(),
+		`{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[2,0]]},"id":1,"properties":{"a":1}}`)
+	expect(t, Simplify(PO(1, 2), nil).JSON() == PO(1, 2).JSON())
+}
+
+func TestSimplifyBoston(t *testing.T) {
+	data, err := ioutil.ReadFile("test_files/boston_subset.geojson")
+	expect(t, err == nil)
+	g := expectJSON(t, string(data), nil)
+	var before, after int
+	for _, child := range g.(*FeatureCollection).Children() {
+		simple := Simplify(child, &SimplifyOptions{
+			Tolerance: 5, Meters: true, PreserveTopology: true,
+		})
+		before += child.NumPoints()
+		after += simple.NumPoints()
+		// rings that were valid stay valid
+		expect(t, polygonConflicts(child) != 0 ||
+			polygonConflicts(simple) == 0)
+	}
+	expect(t, after < before)
+}
+
+func polygonConflicts(obj Object) int {
+	poly, ok := obj.(*Feature).base.(*Polygon)
+	if !ok {
+		return 0
+	}
+	var rings [][]geometry.Point
+	var owners []int
+	var holes []bool
+	for i, ring := range append([]geometry.Ring{poly.base.Exterior},
+		poly.base.Holes...) {
+		rings = append(rings, seriesPoints(ring))
+		owners = append(owners, 0)
+		holes = append(holes, i > 0)
+	}
+	return len(ringConflicts(rings, make([][]int, len(rings)), owners, holes))
+}
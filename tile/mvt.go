@@ -0,0 +1,971 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package tile
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/gjson"
+)
+
+var errMVTInvalid = errors.New("invalid vector tile")
+
+// Mapbox Vector Tile geometry types
+const (
+	mvtPoint      = 1
+	mvtLineString = 2
+	mvtPolygon    = 3
+)
+
+// Mapbox Vector Tile geometry commands
+const (
+	mvtMoveTo    = 1
+	mvtLineTo    = 2
+	mvtClosePath = 7
+)
+
+// MVTOptions are the options for encoding a Mapbox Vector Tile.
+type MVTOptions struct {
+	// Layer is the name of the layer of the features.
+	// The default is "features".
+	Layer string
+	// Extent is the width and height of the tile in the integer coordinates
+	// of the geometries. The default is 4096.
+	Extent int
+	// Buffer is the distance past the edges of the tile, in the integer
+	// coordinates of the geometries, that the geometries are clipped to.
+	// The default is 64.
+	Buffer int
+}
+
+// DefaultMVTOptions are the options that AppendMVT uses when the options are
+// nil. An empty Layer or an Extent of zero also uses its default.
+var DefaultMVTOptions = &MVTOptions{
+	Layer:  "features",
+	Extent: 4096,
+	Buffer: 64,
+}
+
+// mvtPt is a point in the coordinates of the tile, where Y is down.
+type mvtPt struct {
+	x, y float64
+}
+
+// mvtGeoms are the geometries of a feature, in the coordinates of the tile.
+type mvtGeoms struct {
+	points []mvtPt
+	lines  [][]mvtPt
+	rings  [][][]mvtPt // each polygon is an exterior ring and its holes
+}
+
+// mvtEncoder encodes the features of a layer.
+type mvtEncoder struct {
+	tile     Tile
+	n        float64 // number of tiles across the zoom
+	extent   float64
+	min, max float64 // the clip bounds in the coordinates of the tile
+	keys     []string
+	keyIdxs  map[string]int
+	values   [][]byte
+	valIdxs  map[string]int
+	features [][]byte
+	geom     []uint32
+}
+
+// AppendMVT appends the object as a Mapbox Vector Tile with a single layer.
+// Each feature of a FeatureCollection, or each child of another collection,
+// is clipped to the tile, projected to the integer coordinates of the tile,
+// and written as a feature with its "id" and "properties". Properties that
+// are objects or arrays are written as json strings. A feature with more
+// than one kind of geometry, such as a GeometryCollection of a Point and a
+// Polygon, is written as a feature for each kind.
+func AppendMVT(dst []byte, obj geojson.Object, t Tile, opts *MVTOptions,
+) []byte {
+	if opts == nil {
+		opts = DefaultMVTOptions
+	}
+	layer := opts.Layer
+	if layer == "" {
+		layer = DefaultMVTOptions.Layer
+	}
+	extent := opts.Extent
+	if extent <= 0 {
+		extent = DefaultMVTOptions.Extent
+	}
+	e := &mvtEncoder{
+		tile:    t,
+		n:       float64(uint(1) << uint(t.Z)),
+		extent:  float64(extent),
+		min:     -float64(opts.Buffer),
+		max:     float64(extent + opts.Buffer),
+		keyIdxs: make(map[string]int),
+		valIdxs: make(map[string]int),
+	}
+	var features []geojson.Object
+	switch obj.(type) {
+	case *geojson.FeatureCollection, *geojson.GeometryCollection:
+		features = obj.(geojson.Collection).Children()
+	default:
+		features = []geojson.Object{obj}
+	}
+	for _, feature := range features {
+		e.addFeature(feature)
+	}
+	var msg []byte
+	msg = mvtAppendVarintField(msg, 15, 2)
+	msg = mvtAppendBytesField(msg, 1, []byte(layer))
+	for _, feature := range e.features {
+		msg = mvtAppendBytesField(msg, 2, feature)
+	}
+	for _, key := range e.keys {
+		msg = mvtAppendBytesField(msg, 3, []byte(key))
+	}
+	for _, value := range e.values {
+		msg = mvtAppendBytesField(msg, 4, value)
+	}
+	msg = mvtAppendVarintField(msg, 5, uint64(extent))
+	return mvtAppendBytesField(dst, 3, msg)
+}
+
+// project returns a coordinate in the coordinates of the tile.
+func (e *mvtEncoder) project(point geometry.Point) mvtPt {
+	lat := math.Max(-MaxLat, math.Min(MaxLat, point.Y)) * math.Pi / 180
+	x := (point.X + 180) / 360 * e.n
+	y := (1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * e.n
+	return mvtPt{
+		x: (x - float64(e.tile.X)) * e.extent,
+		y: (y - float64(e.tile.Y)) * e.extent,
+	}
+}
+
+func (e *mvtEncoder) projectSeries(series geometry.Series) []mvtPt {
+	points := make([]mvtPt, series.NumPoints())
+	for i := range points {
+		points[i] = e.project(series.PointAt(i))
+	}
+	return points
+}
+
+// collect adds the geometries of an object, in the coordinates of the tile.
+func (e *mvtEncoder) collect(obj geojson.Object, g *mvtGeoms) {
+	switch obj := obj.(type) {
+	case *geojson.Point:
+		g.points = append(g.points, e.project(obj.Base()))
+	case *geojson.SimplePoint:
+		g.points = append(g.points, e.project(obj.Base()))
+	case *geojson.LineString:
+		g.lines = append(g.lines, e.projectSeries(obj.Base()))
+	case *geojson.Polygon:
+		poly := obj.Base()
+		rings := [][]mvtPt{e.projectSeries(poly.Exterior)}
+		for _, hole := range poly.Holes {
+			rings = append(rings, e.projectSeries(hole))
+		}
+		g.rings = append(g.rings, rings)
+	case *geojson.Rect:
+		rect := obj.Base()
+		g.rings = append(g.rings, [][]mvtPt{{
+			e.project(rect.Min),
+			e.project(geometry.Point{X: rect.Max.X, Y: rect.Min.Y}),
+			e.project(rect.Max),
+			e.project(geometry.Point{X: rect.Min.X, Y: rect.Max.Y}),
+			e.project(rect.Min),
+		}})
+	case *geojson.Circle:
+		e.collect(obj.Polygon(), g)
+	case *geojson.Feature:
+		e.collect(obj.Base(), g)
+	case geojson.Collection:
+		for _, child := range obj.Children() {
+			e.collect(child, g)
+		}
+	}
+}
+
+// addFeature clips and adds the geometries of a feature, with its tags.
+func (e *mvtEncoder) addFeature(obj geojson.Object) {
+	var g mvtGeoms
+	e.collect(obj, &g)
+	var id uint64
+	var hasID bool
+	var props gjson.Result
+	if members := obj.Members(); members != "" {
+		rID := gjson.Get(members, "id")
+		if rID.Type == gjson.Number && rID.Num >= 0 &&
+			rID.Num == math.Trunc(rID.Num) && rID.Num < 1<<63 {
+			id, hasID = rID.Uint(), true
+		}
+		props = gjson.Get(members, "properties")
+	}
+	// the tags are only added for a feature that is in the tile
+	var tags []uint32
+	var tagged bool
+	add := func(typ uint32) {
+		if len(e.geom) == 0 {
+			return
+		}
+		if !tagged {
+			props.ForEach(func(key, value gjson.Result) bool {
+				if value.Type != gjson.Null {
+					tags = append(tags, e.keyIndex(key.String()),
+						e.valueIndex(value))
+				}
+				return true
+			})
+			tagged = true
+		}
+		var feature []byte
+		if hasID {
+			feature = mvtAppendVarintField(feature, 1, id)
+		}
+		if len(tags) > 0 {
+			feature = mvtAppendPackedField(feature, 2, tags)
+		}
+		feature = mvtAppendVarintField(feature, 3, uint64(typ))
+		feature = mvtAppendPackedField(feature, 4, e.geom)
+		e.features = append(e.features, feature)
+	}
+	e.encodePoints(g.points)
+	add(mvtPoint)
+	e.encodeLines(g.lines)
+	add(mvtLineString)
+	e.encodePolygons(g.rings)
+	add(mvtPolygon)
+}
+
+func (e *mvtEncoder) keyIndex(key string) uint32 {
+	idx, ok := e.keyIdxs[key]
+	if !ok {
+		idx = len(e.keys)
+		e.keys = append(e.keys, key)
+		e.keyIdxs[key] = idx
+	}
+	return uint32(idx)
+}
+
+// valueIndex returns the index of a value. Integers are unsigned or signed
+// integers, other numbers are doubles, and objects and arrays are strings.
+func (e *mvtEncoder) valueIndex(value gjson.Result) uint32 {
+	var val []byte
+	switch value.Type {
+	case gjson.String:
+		val = mvtAppendBytesField(val, 1, []byte(value.Str))
+	case gjson.True, gjson.False:
+		var b uint64
+		if value.Bool() {
+			b = 1
+		}
+		val = mvtAppendVarintField(val, 7, b)
+	case gjson.Number:
+		num := value.Num
+		switch {
+		case num != math.Trunc(num) || math.Abs(num) >= 1<<63:
+			val = mvtAppendFixed64Field(val, 3, math.Float64bits(num))
+		case num >= 0:
+			val = mvtAppendVarintField(val, 5, uint64(num))
+		default:
+			val = mvtAppendVarintField(val, 6, mvtZigzag(int64(num)))
+		}
+	default:
+		val = mvtAppendBytesField(val, 1, []byte(value.Raw))
+	}
+	idx, ok := e.valIdxs[string(val)]
+	if !ok {
+		idx = len(e.values)
+		e.values = append(e.values, val)
+		e.valIdxs[string(val)] = idx
+	}
+	return uint32(idx)
+}
+
+// mvtCursor is the current point of a geometry command stream.
+type mvtCursor struct {
+	x, y int64
+}
+
+func (e *mvtEncoder) appendPoint(c *mvtCursor, x, y int64) {
+	e.geom = append(e.geom,
+		uint32(mvtZigzag(x-c.x)), uint32(mvtZigzag(y-c.y)))
+	c.x, c.y = x, y
+}
+
+func mvtCommand(id, count int) uint32 {
+	return uint32(id&0x7 | count<<3)
+}
+
+// round returns the integer coordinates of the points, without points that
+// are the same as the previous point.
+func mvtRound(points []mvtPt) [][2]int64 {
+	var ints [][2]int64
+	for _, pt := range points {
+		p := [2]int64{int64(math.Round(pt.x)), int64(math.Round(pt.y))}
+		if len(ints) == 0 || ints[len(ints)-1] != p {
+			ints = append(ints, p)
+		}
+	}
+	return ints
+}
+
+func (e *mvtEncoder) encodePoints(points []mvtPt) {
+	e.geom = e.geom[:0]
+	var ints [][2]int64
+	for _, pt := range points {
+		if pt.x >= e.min && pt.x <= e.max && pt.y >= e.min && pt.y <= e.max {
+			ints = append(ints, [2]int64{
+				int64(math.Round(pt.x)), int64(math.Round(pt.y)),
+			})
+		}
+	}
+	if len(ints) == 0 {
+		return
+	}
+	var c mvtCursor
+	e.geom = append(e.geom, mvtCommand(mvtMoveTo, len(ints)))
+	for _, p := range ints {
+		e.appendPoint(&c, p[0], p[1])
+	}
+}
+
+func (e *mvtEncoder) encodeLines(lines [][]mvtPt) {
+	e.geom = e.geom[:0]
+	var c mvtCursor
+	for _, line := range lines {
+		for _, part := range mvtClipLine(line, e.min, e.max) {
+			ints := mvtRound(part)
+			if len(ints) < 2 {
+				continue
+			}
+			e.geom = append(e.geom, mvtCommand(mvtMoveTo, 1))
+			e.appendPoint(&c, ints[0][0], ints[0][1])
+			e.geom = append(e.geom, mvtCommand(mvtLineTo, len(ints)-1))
+			for _, p := range ints[1:] {
+				e.appendPoint(&c, p[0], p[1])
+			}
+		}
+	}
+}
+
+// encodePolygons encodes the rings of the polygons, where the exterior rings
+// have a positive area and the holes have a negative area.
+func (e *mvtEncoder) encodePolygons(polys [][][]mvtPt) {
+	e.geom = e.geom[:0]
+	var c mvtCursor
+	for _, rings := range polys {
+		for i, ring := range rings {
+			ints := mvtRound(mvtClipRing(ring, e.min, e.max))
+			if len(ints) > 1 && ints[0] == ints[len(ints)-1] {
+				ints = ints[:len(ints)-1]
+			}
+			area := mvtArea(ints)
+			if len(ints) < 3 || area == 0 {
+				if i == 0 {
+					// the holes of a polygon without an exterior
+					break
+				}
+				continue
+			}
+			if (i == 0) != (area > 0) {
+				for j := 0; j < len(ints)/2; j++ {
+					ints[j], ints[len(ints)-1-j] = ints[len(ints)-1-j], ints[j]
+				}
+			}
+			e.geom = append(e.geom, mvtCommand(mvtMoveTo, 1))
+			e.appendPoint(&c, ints[0][0], ints[0][1])
+			e.geom = append(e.geom, mvtCommand(mvtLineTo, len(ints)-1))
+			for _, p := range ints[1:] {
+				e.appendPoint(&c, p[0], p[1])
+			}
+			e.geom = append(e.geom, mvtCommand(mvtClosePath, 1))
+		}
+	}
+}
+
+// mvtArea returns twice the area of a ring using the surveyor's formula,
+// which is positive for a ring that is clockwise when Y is down.
+func mvtArea(ring [][2]int64) int64 {
+	var area int64
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		area += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return area
+}
+
+// mvtClipRing clips a ring to the square from min to max, using the
+// Sutherland-Hodgman algorithm.
+func mvtClipRing(ring []mvtPt, min, max float64) []mvtPt {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	// each edge is the axis, the bound, and whether the inside is below it
+	edges := [4]struct {
+		x     bool
+		bound float64
+		below bool
+	}{{true, min, false}, {true, max, true}, {false, min, false},
+		{false, max, true}}
+	for _, edge := range edges {
+		if len(ring) == 0 {
+			break
+		}
+		inside := func(pt mvtPt) bool {
+			v := pt.y
+			if edge.x {
+				v = pt.x
+			}
+			if edge.below {
+				return v <= edge.bound
+			}
+			return v >= edge.bound
+		}
+		cross := func(a, b mvtPt) mvtPt {
+			if edge.x {
+				t := (edge.bound - a.x) / (b.x - a.x)
+				return mvtPt{edge.bound, a.y + (b.y-a.y)*t}
+			}
+			t := (edge.bound - a.y) / (b.y - a.y)
+			return mvtPt{a.x + (b.x-a.x)*t, edge.bound}
+		}
+		var clipped []mvtPt
+		prev := ring[len(ring)-1]
+		for _, pt := range ring {
+			if inside(pt) {
+				if !inside(prev) {
+					clipped = append(clipped, cross(prev, pt))
+				}
+				clipped = append(clipped, pt)
+			} else if inside(prev) {
+				clipped = append(clipped, cross(prev, pt))
+			}
+			prev = pt
+		}
+		ring = clipped
+	}
+	return ring
+}
+
+// mvtClipLine clips a line to the square from min to max, using the
+// Liang-Barsky algorithm on each segment. The parts of the line that are
+// inside are returned.
+func mvtClipLine(line []mvtPt, min, max float64) [][]mvtPt {
+	var parts [][]mvtPt
+	var part []mvtPt
+	for i := 0; i < len(line)-1; i++ {
+		a, b := line[i], line[i+1]
+		t0, t1 := 0.0, 1.0
+		dx, dy := b.x-a.x, b.y-a.y
+		ok := true
+		for _, c := range [4][2]float64{
+			{-dx, a.x - min}, {dx, max - a.x}, {-dy, a.y - min}, {dy, max - a.y},
+		} {
+			p, q := c[0], c[1]
+			if p == 0 {
+				if q < 0 {
+					ok = false
+					break
+				}
+				continue
+			}
+			r := q / p
+			if p < 0 {
+				if r > t1 {
+					ok = false
+					break
+				}
+				if r > t0 {
+					t0 = r
+				}
+			} else {
+				if r < t0 {
+					ok = false
+					break
+				}
+				if r < t1 {
+					t1 = r
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		start := mvtPt{a.x + dx*t0, a.y + dy*t0}
+		end := mvtPt{a.x + dx*t1, a.y + dy*t1}
+		if len(part) > 0 && part[len(part)-1] != start {
+			parts = append(parts, part)
+			part = nil
+		}
+		if len(part) == 0 {
+			part = append(part, start)
+		}
+		part = append(part, end)
+	}
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func mvtZigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func mvtUnzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func mvtAppendVarint(dst []byte, v uint64) []byte {
+	for v >= 0x80 {
+		dst = append(dst, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(dst, byte(v))
+}
+
+func mvtAppendVarintField(dst []byte, field int, v uint64) []byte {
+	dst = mvtAppendVarint(dst, uint64(field<<3))
+	return mvtAppendVarint(dst, v)
+}
+
+func mvtAppendFixed64Field(dst []byte, field int, v uint64) []byte {
+	dst = mvtAppendVarint(dst, uint64(field<<3|1))
+	for i := 0; i < 8; i++ {
+		dst = append(dst, byte(v>>(i*8)))
+	}
+	return dst
+}
+
+func mvtAppendBytesField(dst []byte, field int, b []byte) []byte {
+	dst = mvtAppendVarint(dst, uint64(field<<3|2))
+	dst = mvtAppendVarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+func mvtAppendPackedField(dst []byte, field int, vals []uint32) []byte {
+	var packed []byte
+	for _, v := range vals {
+		packed = mvtAppendVarint(packed, uint64(v))
+	}
+	return mvtAppendBytesField(dst, field, packed)
+}
+
+// mvtReader reads the fields of a protocol buffers message.
+type mvtReader struct {
+	data []byte
+	pos  int
+}
+
+func (rd *mvtReader) varint() (uint64, bool) {
+	var v uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if rd.pos == len(rd.data) {
+			return 0, false
+		}
+		b := rd.data[rd.pos]
+		rd.pos++
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// next reads the next field. The value is the varint, or the bits of a fixed
+// number, and the bytes are the bytes of a length-delimited field.
+func (rd *mvtReader) next() (field int, value uint64, bytes []byte, err error) {
+	key, ok := rd.varint()
+	if !ok {
+		return 0, 0, nil, errMVTInvalid
+	}
+	field = int(key >> 3)
+	switch key & 7 {
+	case 0:
+		value, ok = rd.varint()
+	case 1, 5:
+		size := 8
+		if key&7 == 5 {
+			size = 4
+		}
+		if ok = len(rd.data)-rd.pos >= size; ok {
+			for i := 0; i < size; i++ {
+				value |= uint64(rd.data[rd.pos+i]) << (i * 8)
+			}
+			rd.pos += size
+		}
+	case 2:
+		var n uint64
+		if n, ok = rd.varint(); ok && n <= uint64(len(rd.data)-rd.pos) {
+			bytes = rd.data[rd.pos : rd.pos+int(n)]
+			rd.pos += int(n)
+		} else {
+			ok = false
+		}
+	default:
+		ok = false
+	}
+	if !ok {
+		return 0, 0, nil, errMVTInvalid
+	}
+	return field, value, bytes, nil
+}
+
+// mvtPacked reads packed varints.
+func mvtPacked(data []byte) ([]uint32, error) {
+	rd := mvtReader{data: data}
+	var vals []uint32
+	for rd.pos < len(rd.data) {
+		v, ok := rd.varint()
+		if !ok {
+			return nil, errMVTInvalid
+		}
+		vals = append(vals, uint32(v))
+	}
+	return vals, nil
+}
+
+// mvtLayer is a layer that is being decoded.
+type mvtLayer struct {
+	name     string
+	extent   float64
+	keys     []string
+	values   [][]byte // values as json
+	features [][]byte
+}
+
+// ParseMVT parses a Mapbox Vector Tile at a tile, and returns a
+// FeatureCollection for each of its layers, by the name of the layer. The
+// coordinates are converted to longitude and latitude. The features are
+// parsed using the provided options.
+func ParseMVT(data []byte, t Tile, opts *geojson.ParseOptions) (
+	map[string]*geojson.FeatureCollection, error,
+) {
+	layers := make(map[string]*geojson.FeatureCollection)
+	rd := mvtReader{data: data}
+	for rd.pos < len(rd.data) {
+		field, _, bytes, err := rd.next()
+		if err != nil {
+			return nil, err
+		}
+		if field != 3 {
+			continue
+		}
+		layer, err := parseMVTLayer(bytes)
+		if err != nil {
+			return nil, err
+		}
+		json, err := layer.appendJSON(nil, t)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := geojson.Parse(string(json), opts)
+		if err != nil {
+			return nil, err
+		}
+		fc, ok := obj.(*geojson.FeatureCollection)
+		if !ok {
+			return nil, errMVTInvalid
+		}
+		layers[layer.name] = fc
+	}
+	return layers, nil
+}
+
+func parseMVTLayer(data []byte) (*mvtLayer, error) {
+	layer := &mvtLayer{extent: 4096}
+	rd := mvtReader{data: data}
+	for rd.pos < len(rd.data) {
+		field, value, bytes, err := rd.next()
+		if err != nil {
+			return nil, err
+		}
+		switch field {
+		case 1:
+			layer.name = string(bytes)
+		case 2:
+			layer.features = append(layer.features, bytes)
+		case 3:
+			layer.keys = append(layer.keys, string(bytes))
+		case 4:
+			val, err := parseMVTValue(bytes)
+			if err != nil {
+				return nil, err
+			}
+			layer.values = append(layer.values, val)
+		case 5:
+			if value == 0 {
+				return nil, errMVTInvalid
+			}
+			layer.extent = float64(value)
+		}
+	}
+	return layer, nil
+}
+
+// parseMVTValue returns a value as json.
+func parseMVTValue(data []byte) ([]byte, error) {
+	rd := mvtReader{data: data}
+	var val []byte
+	for rd.pos < len(rd.data) {
+		field, value, bytes, err := rd.next()
+		if err != nil {
+			return nil, err
+		}
+		switch field {
+		case 1:
+			val, _ = json.Marshal(string(bytes))
+		case 2:
+			val = mvtAppendFloat(val[:0],
+				float64(math.Float32frombits(uint32(value))))
+		case 3:
+			val = mvtAppendFloat(val[:0], math.Float64frombits(value))
+		case 4:
+			val = strconv.AppendInt(val[:0], int64(value), 10)
+		case 5:
+			val = strconv.AppendUint(val[:0], value, 10)
+		case 6:
+			val = strconv.AppendInt(val[:0], mvtUnzigzag(value), 10)
+		case 7:
+			val = strconv.AppendBool(val[:0], value != 0)
+		}
+	}
+	if val == nil {
+		val = []byte("null")
+	}
+	return val, nil
+}
+
+func mvtAppendFloat(dst []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return append(dst, "null"...)
+	}
+	return strconv.AppendFloat(dst, f, 'f', -1, 64)
+}
+
+// appendJSON appends the layer as a GeoJSON FeatureCollection.
+func (layer *mvtLayer) appendJSON(dst []byte, t Tile) ([]byte, error) {
+	dst = append(dst, `{"type":"FeatureCollection","features":[`...)
+	var n int
+	for _, data := range layer.features {
+		var id uint64
+		var hasID bool
+		var tags, geom []uint32
+		var typ uint64
+		rd := mvtReader{data: data}
+		for rd.pos < len(rd.data) {
+			field, value, bytes, err := rd.next()
+			if err != nil {
+				return nil, err
+			}
+			switch field {
+			case 1:
+				id, hasID = value, true
+			case 2:
+				if tags, err = mvtPacked(bytes); err != nil {
+					return nil, err
+				}
+			case 3:
+				typ = value
+			case 4:
+				if geom, err = mvtPacked(bytes); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if len(tags)%2 != 0 {
+			return nil, errMVTInvalid
+		}
+		mark := len(dst)
+		if n > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `{"type":"Feature"`...)
+		if hasID {
+			dst = append(dst, `,"id":`...)
+			dst = strconv.AppendUint(dst, id, 10)
+		}
+		dst = append(dst, `,"geometry":`...)
+		var ok bool
+		dst, ok = layer.appendGeometry(dst, t, typ, geom)
+		if !ok {
+			// a feature with an unknown type, or without a geometry, is
+			// ignored
+			dst = dst[:mark]
+			continue
+		}
+		dst = append(dst, `,"properties":{`...)
+		for i := 0; i < len(tags); i += 2 {
+			if int(tags[i]) >= len(layer.keys) ||
+				int(tags[i+1]) >= len(layer.values) {
+				return nil, errMVTInvalid
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			key, _ := json.Marshal(layer.keys[tags[i]])
+			dst = append(dst, key...)
+			dst = append(dst, ':')
+			dst = append(dst, layer.values[tags[i+1]]...)
+		}
+		dst = append(dst, "}}"...)
+		n++
+	}
+	return append(dst, "]}"...), nil
+}
+
+// unproject returns the longitude and latitude of a coordinate of the tile.
+func (layer *mvtLayer) unproject(t Tile, x, y int64) geometry.Point {
+	n := float64(uint(1) << uint(t.Z))
+	wx := (float64(t.X) + float64(x)/layer.extent) / n
+	wy := (float64(t.Y) + float64(y)/layer.extent) / n
+	return geometry.Point{
+		X: wx*360 - 180,
+		Y: math.Atan(math.Sinh(math.Pi*(1-2*wy))) * 180 / math.Pi,
+	}
+}
+
+// appendGeometry appends the geometry command stream as a GeoJSON geometry.
+// It returns false for an unknown type, or a geometry that is not valid.
+func (layer *mvtLayer) appendGeometry(dst []byte, t Tile, typ uint64,
+	geom []uint32,
+) ([]byte, bool) {
+	// read the command stream into lines, where the rings are closed
+	var lines [][][2]int64
+	var x, y int64
+	for i := 0; i < len(geom); {
+		id, count := int(geom[i]&0x7), int(geom[i]>>3)
+		i++
+		switch id {
+		case mvtMoveTo, mvtLineTo:
+			if len(geom)-i < count*2 || id == mvtLineTo && len(lines) == 0 {
+				return dst, false
+			}
+			for j := 0; j < count; j++ {
+				x += mvtUnzigzag(uint64(geom[i]))
+				y += mvtUnzigzag(uint64(geom[i+1]))
+				i += 2
+				if id == mvtMoveTo && (j == 0 || typ == mvtPoint) {
+					lines = append(lines, nil)
+				}
+				lines[len(lines)-1] = append(lines[len(lines)-1],
+					[2]int64{x, y})
+			}
+		case mvtClosePath:
+			if len(lines) == 0 {
+				return dst, false
+			}
+			line := lines[len(lines)-1]
+			lines[len(lines)-1] = append(line, line[0])
+		default:
+			return dst, false
+		}
+	}
+	if len(lines) == 0 {
+		return dst, false
+	}
+	appendLine := func(dst []byte, line [][2]int64) []byte {
+		dst = append(dst, '[')
+		for i, p := range line {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			point := layer.unproject(t, p[0], p[1])
+			dst = append(dst, '[')
+			dst = mvtAppendFloat(dst, point.X)
+			dst = append(dst, ',')
+			dst = mvtAppendFloat(dst, point.Y)
+			dst = append(dst, ']')
+		}
+		return append(dst, ']')
+	}
+	switch typ {
+	case mvtPoint:
+		if len(lines) == 1 {
+			dst = append(dst, `{"type":"Point","coordinates":`...)
+			line := appendLine(nil, lines[0])
+			dst = append(dst, line[1:len(line)-1]...)
+		} else {
+			dst = append(dst, `{"type":"MultiPoint","coordinates":[`...)
+			for i, line := range lines {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				line := appendLine(nil, line)
+				dst = append(dst, line[1:len(line)-1]...)
+			}
+			dst = append(dst, ']')
+		}
+	case mvtLineString:
+		if len(lines) == 1 {
+			dst = append(dst, `{"type":"LineString","coordinates":`...)
+			dst = appendLine(dst, lines[0])
+		} else {
+			dst = append(dst, `{"type":"MultiLineString","coordinates":[`...)
+			for i, line := range lines {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				dst = appendLine(dst, line)
+			}
+			dst = append(dst, ']')
+		}
+	case mvtPolygon:
+		// each ring with a positive area starts a polygon, and the rings
+		// with a negative area are its holes
+		var polys [][][][2]int64
+		for _, ring := range lines {
+			area := mvtArea(ring)
+			if area == 0 {
+				continue
+			}
+			if area > 0 || len(polys) == 0 {
+				polys = append(polys, nil)
+			}
+			if area < 0 && len(polys[len(polys)-1]) == 0 {
+				// an exterior ring in the other direction
+				for j := 0; j < len(ring)/2; j++ {
+					ring[j], ring[len(ring)-1-j] = ring[len(ring)-1-j], ring[j]
+				}
+			}
+			polys[len(polys)-1] = append(polys[len(polys)-1], ring)
+		}
+		appendPoly := func(dst []byte, rings [][][2]int64) []byte {
+			dst = append(dst, '[')
+			for i := range rings {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				// the rings are written counter-clockwise, with clockwise
+				// holes, after the Y axis is flipped
+				ring := rings[i]
+				for j := 0; j < len(ring)/2; j++ {
+					ring[j], ring[len(ring)-1-j] = ring[len(ring)-1-j], ring[j]
+				}
+				dst = appendLine(dst, ring)
+			}
+			return append(dst, ']')
+		}
+		if len(polys) == 1 {
+			dst = append(dst, `{"type":"Polygon","coordinates":`...)
+			dst = appendPoly(dst, polys[0])
+		} else {
+			dst = append(dst, `{"type":"MultiPolygon","coordinates":[`...)
+			for i, poly := range polys {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				dst = appendPoly(dst, poly)
+			}
+			dst = append(dst, ']')
+		}
+	default:
+		return dst, false
+	}
+	return append(dst, '}'), true
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package tile
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/gjson"
+)
+
+func parseJSON(t *testing.T, json string) geojson.Object {
+	t.Helper()
+	obj, err := geojson.Parse(json, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestMVT(t *testing.T) {
+	tile := Tile{X: 163, Y: 395, Z: 10}
+	rect := tile.Rect()
+	// a coordinate at the fraction of the tile
+	at := func(fx, fy float64) string {
+		x := rect.Min.X + (rect.Max.X-rect.Min.X)*fx
+		y := rect.Min.Y + (rect.Max.Y-rect.Min.Y)*fy
+		return "[" + ftoa(x) + "," + ftoa(y) + "]"
+	}
+	fc := parseJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":7,"geometry":{"type":"Point",
+		 "coordinates":`+at(0.5, 0.5)+`},
+		 "properties":{"name":"a","n":-3,"u":5,"f":1.5,"b":true,"z":null,
+		 "o":{"a":[1]}}},
+		{"type":"Feature","geometry":{"type":"LineString",
+		 "coordinates":[`+at(0.1, 0.1)+`,`+at(0.9, 0.9)+`]},
+		 "properties":{"name":"a"}},
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":[
+		 [`+at(0.2, 0.2)+`,`+at(0.8, 0.2)+`,`+at(0.8, 0.8)+`,`+at(0.2, 0.8)+`,`+
+		at(0.2, 0.2)+`],
+		 [`+at(0.4, 0.4)+`,`+at(0.4, 0.6)+`,`+at(0.6, 0.6)+`,`+at(0.6, 0.4)+`,`+
+		at(0.4, 0.4)+`]]},
+		 "properties":{}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},
+		 "properties":{"outside":true}}
+	]}`)
+	data := AppendMVT(nil, fc, tile, &MVTOptions{Layer: "things"})
+	layers, err := ParseMVT(data, tile, nil)
+	expect(t, err == nil)
+	expect(t, len(layers) == 1)
+	children := layers["things"].Children()
+	expect(t, len(children) == 3)
+	orig := fc.(geojson.Collection).Children()
+	eps := (rect.Max.X - rect.Min.X) / 4096
+	for i, child := range children {
+		a, b := child.Rect(), orig[i].Rect()
+		expect(t, math.Abs(a.Min.X-b.Min.X) < eps &&
+			math.Abs(a.Min.Y-b.Min.Y) < eps &&
+			math.Abs(a.Max.X-b.Max.X) < eps &&
+			math.Abs(a.Max.Y-b.Max.Y) < eps)
+	}
+	members := children[0].Members()
+	expect(t, gjson.Get(members, "id").Int() == 7)
+	expect(t, gjson.Get(members, "properties").Raw ==
+		`{"name":"a","n":-3,"u":5,"f":1.5,"b":true,"o":"{\"a\":[1]}"}`)
+	expect(t, gjson.Get(children[1].Members(), "properties").Raw ==
+		`{"name":"a"}`)
+	expect(t, !gjson.Get(children[1].Members(), "id").Exists())
+	// the polygon has its hole, and the rings are in the GeoJSON order
+	poly := children[2].(*geojson.Feature).Base().(*geojson.Polygon).Base()
+	expect(t, len(poly.Holes) == 1)
+	expect(t, !poly.Exterior.Clockwise() && poly.Holes[0].Clockwise())
+	expect(t, !children[2].Contains(parseJSON(t,
+		`{"type":"Point","coordinates":`+at(0.5, 0.5)+`}`)))
+	expect(t, children[2].Contains(parseJSON(t,
+		`{"type":"Point","coordinates":`+at(0.3, 0.3)+`}`)))
+
+	// the keys and values are shared
+	layer := mvtLayerOf(t, data)
+	expect(t, len(layer.keys) == 6 && len(layer.values) == 6)
+
+	// the geometry commands of a point, from the specification
+	point := (&mvtLayer{extent: 4096}).unproject(tile, 25, 17)
+	data = AppendMVT(nil, geojson.NewPoint(point), tile, nil)
+	layer = mvtLayerOf(t, data)
+	expect(t, layer.name == "features" && len(layer.features) == 1)
+	expect(t, bytes.Contains(layer.features[0], []byte{0x22, 3, 9, 50, 34}))
+}
+
+func TestMVTClip(t *testing.T) {
+	tile := Tile{X: 1, Y: 1, Z: 2}
+	rect := tile.Rect()
+	// a polygon around the tile
+	poly := geojson.NewPolygon(geometry.NewPoly([]geometry.Point{
+		{X: -170, Y: -80}, {X: 170, Y: -80}, {X: 170, Y: 80}, {X: -170, Y: 80},
+		{X: -170, Y: -80},
+	}, nil, nil))
+	// a line that goes in and out of the tile
+	line := geojson.NewLineString(geometry.NewLine([]geometry.Point{
+		{X: -100, Y: 10}, {X: -80, Y: 10}, {X: -80, Y: 80}, {X: -10, Y: 80},
+		{X: -10, Y: 10}, {X: 10, Y: 10},
+	}, nil))
+	fc := geojson.NewFeatureCollection([]geojson.Object{poly, line})
+	layers, err := ParseMVT(AppendMVT(nil, fc, tile, nil), tile, nil)
+	expect(t, err == nil)
+	children := layers["features"].Children()
+	expect(t, len(children) == 2)
+	buffer := (rect.Max.X - rect.Min.X) * 64 / 4096
+	prect := children[0].Rect()
+	expect(t, math.Abs(prect.Min.X-(rect.Min.X-buffer)) < 1e-6)
+	expect(t, math.Abs(prect.Max.X-(rect.Max.X+buffer)) < 1e-6)
+	expect(t, prect.Max.Y > rect.Max.Y && prect.Max.Y < 80)
+	mls, ok := children[1].(*geojson.Feature).Base().(*geojson.MultiLineString)
+	expect(t, ok && len(mls.Children()) == 2)
+
+	// without a buffer
+	layers, err = ParseMVT(AppendMVT(nil, fc, tile, &MVTOptions{Extent: 512}),
+		tile, nil)
+	expect(t, err == nil)
+	prect = layers["features"].Children()[0].Rect()
+	expect(t, math.Abs(prect.Min.X-rect.Min.X) < 1e-9)
+	expect(t, math.Abs(prect.Max.Y-rect.Max.Y) < 1e-9)
+}
+
+func TestMVTParse(t *testing.T) {
+	tile := Tile{}
+	// two layers, written by hand
+	var layer []byte
+	layer = mvtAppendVarintField(layer, 15, 2)
+	layer = mvtAppendBytesField(layer, 1, []byte("a"))
+	var feature []byte
+	feature = mvtAppendPackedField(feature, 2, []uint32{0, 0})
+	feature = mvtAppendVarintField(feature, 3, mvtPoint)
+	feature = mvtAppendPackedField(feature, 4, []uint32{
+		mvtCommand(mvtMoveTo, 2), 4, 4, 2, 2,
+	})
+	layer = mvtAppendBytesField(layer, 2, feature)
+	layer = mvtAppendBytesField(layer, 3, []byte("k"))
+	var value []byte
+	value = mvtAppendFixed64Field(value, 3, math.Float64bits(0.25))
+	layer = mvtAppendBytesField(layer, 4, value)
+	layer = mvtAppendVarintField(layer, 5, 8)
+	var data []byte
+	data = mvtAppendBytesField(data, 3, layer)
+	layer = nil
+	layer = mvtAppendBytesField(layer, 1, []byte("b"))
+	data = mvtAppendBytesField(data, 3, layer)
+	layers, err := ParseMVT(data, tile, nil)
+	expect(t, err == nil)
+	expect(t, len(layers) == 2 && len(layers["b"].Children()) == 0)
+	obj := layers["a"].Children()[0]
+	expect(t, obj.JSON() == `{"type":"Feature","geometry":{"type":"MultiPoint",`+
+		`"coordinates":[[-90,66.51326044311185],[-45,40.979898069620134]]},`+
+		`"properties":{"k":0.25}}`)
+
+	_, err = ParseMVT([]byte{0x1a, 10, 1}, tile, nil)
+	expect(t, err == errMVTInvalid)
+	_, err = ParseMVT([]byte{0x1a, 2, 0x28, 0}, tile, nil)
+	expect(t, err == errMVTInvalid)
+	layers, err = ParseMVT(nil, tile, nil)
+	expect(t, err == nil && len(layers) == 0)
+}
+
+func mvtLayerOf(t *testing.T, data []byte) *mvtLayer {
+	t.Helper()
+	rd := mvtReader{data: data}
+	field, _, bytes, err := rd.next()
+	expect(t, err == nil && field == 3)
+	layer, err := parseMVTLayer(bytes)
+	expect(t, err == nil)
+	return layer
+}
+
+func ftoa(f float64) string {
+	return string(mvtAppendFloat(nil, f))
+}
@@ -0,0 +1,205 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package tile provides the XYZ tiles and quadkeys of web mercator maps.
+package tile
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+)
+
+// MaxZoom is the maximum zoom of a tile.
+const MaxZoom = 30
+
+// MaxLat is the maximum latitude of the web mercator projection. There are
+// no tiles north of this latitude, or south of its negative.
+var MaxLat = math.Atan(math.Sinh(math.Pi)) * 180 / math.Pi
+
+// Tile is an XYZ tile, where X is the column from the west, Y is the row from
+// the north, and Z is the zoom.
+type Tile struct {
+	X, Y, Z int
+}
+
+// Valid returns true when the tile is at a valid zoom and inside the grid of
+// its zoom.
+func (t Tile) Valid() bool {
+	if t.Z < 0 || t.Z > MaxZoom {
+		return false
+	}
+	n := 1 << uint(t.Z)
+	return t.X >= 0 && t.X < n && t.Y >= 0 && t.Y < n
+}
+
+// String returns the tile as "z/x/y".
+func (t Tile) String() string {
+	var dst []byte
+	dst = strconv.AppendInt(dst, int64(t.Z), 10)
+	dst = append(dst, '/')
+	dst = strconv.AppendInt(dst, int64(t.X), 10)
+	dst = append(dst, '/')
+	dst = strconv.AppendInt(dst, int64(t.Y), 10)
+	return string(dst)
+}
+
+// FromPoint returns the tile at the zoom that contains the point, where X is
+// the longitude and Y is the latitude. A latitude past MaxLat is in the first
+// or last row.
+func FromPoint(point geometry.Point, zoom int) Tile {
+	if zoom < 0 {
+		zoom = 0
+	} else if zoom > MaxZoom {
+		zoom = MaxZoom
+	}
+	n := float64(uint(1) << uint(zoom))
+	lat := math.Max(-MaxLat, math.Min(MaxLat, point.Y)) * math.Pi / 180
+	x := math.Floor((point.X + 180) / 360 * n)
+	y := math.Floor((1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) /
+		2 * n)
+	clamp := func(v float64) int {
+		if !(v >= 0) {
+			return 0
+		}
+		if v >= n {
+			return int(n) - 1
+		}
+		return int(v)
+	}
+	return Tile{X: clamp(x), Y: clamp(y), Z: zoom}
+}
+
+// tileLon returns the longitude of the west edge of a column.
+func tileLon(x int, n float64) float64 {
+	return float64(x)/n*360 - 180
+}
+
+// tileLat returns the latitude of the north edge of a row.
+func tileLat(y int, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi
+}
+
+// Rect returns the bounds of the tile in longitude and latitude.
+func (t Tile) Rect() geometry.Rect {
+	n := float64(uint(1) << uint(t.Z))
+	return geometry.Rect{
+		Min: geometry.Point{X: tileLon(t.X, n), Y: tileLat(t.Y+1, n)},
+		Max: geometry.Point{X: tileLon(t.X+1, n), Y: tileLat(t.Y, n)},
+	}
+}
+
+// Parent returns the tile at the zoom above, which contains the tile. The
+// parent of a tile at zoom zero is the same tile.
+func (t Tile) Parent() Tile {
+	if t.Z == 0 {
+		return t
+	}
+	return Tile{X: t.X >> 1, Y: t.Y >> 1, Z: t.Z - 1}
+}
+
+// Children returns the four tiles at the zoom below, which are inside the
+// tile, in the order northwest, northeast, southwest, and southeast.
+func (t Tile) Children() [4]Tile {
+	x, y, z := t.X<<1, t.Y<<1, t.Z+1
+	return [4]Tile{
+		{X: x, Y: y, Z: z},
+		{X: x + 1, Y: y, Z: z},
+		{X: x, Y: y + 1, Z: z},
+		{X: x + 1, Y: y + 1, Z: z},
+	}
+}
+
+// Quadkey returns the quadkey of the tile, which has one digit for each
+// zoom. The quadkey of a tile at zoom zero is an empty string.
+func (t Tile) Quadkey() string {
+	if t.Z <= 0 {
+		return ""
+	}
+	key := make([]byte, t.Z)
+	for i := 0; i < t.Z; i++ {
+		bit := uint(t.Z - 1 - i)
+		key[i] = '0' + byte(t.X>>bit&1) + byte(t.Y>>bit&1)<<1
+	}
+	return string(key)
+}
+
+// FromQuadkey returns the tile of a quadkey. It returns false when the
+// quadkey is not valid.
+func FromQuadkey(quadkey string) (Tile, bool) {
+	if len(quadkey) > MaxZoom {
+		return Tile{}, false
+	}
+	t := Tile{Z: len(quadkey)}
+	for i := 0; i < len(quadkey); i++ {
+		c := quadkey[i]
+		if c < '0' || c > '3' {
+			return Tile{}, false
+		}
+		t.X = t.X<<1 | int(c-'0')&1
+		t.Y = t.Y<<1 | int(c-'0')>>1
+	}
+	return t, true
+}
+
+// Cover returns the tiles at the zoom that intersect the object.
+func Cover(obj geojson.Object, zoom int) []Tile {
+	return CoverRange(obj, zoom, zoom)
+}
+
+// CoverRange returns the tiles at each zoom, from the min zoom to the max
+// zoom, that intersect the object. The tiles are tested with the geometry
+// of the object, and not only its bounding rectangle. The tiles that only
+// touch the edges of the object are not in the cover, and a point is in the
+// tile of FromPoint. The tiles are ordered by zoom, and then by row and
+// column.
+func CoverRange(obj geojson.Object, minZoom, maxZoom int) []Tile {
+	if minZoom < 0 {
+		minZoom = 0
+	}
+	if maxZoom > MaxZoom {
+		maxZoom = MaxZoom
+	}
+	if obj.Empty() || minZoom > maxZoom {
+		return nil
+	}
+	rect := obj.Rect()
+	var tiles []Tile
+	level := []Tile{{}}
+	for zoom := 0; zoom <= maxZoom && len(level) > 0; zoom++ {
+		var next []Tile
+		for _, t := range level {
+			tileRect := t.Rect()
+			in := func(point geometry.Point) bool {
+				return FromPoint(point, t.Z) == t
+			}
+			if !tileRect.IntersectsRect(rect) ||
+				!geojson.IntersectsCell(obj, tileRect, in) {
+				continue
+			}
+			if zoom >= minZoom {
+				tiles = append(tiles, t)
+			}
+			if zoom < maxZoom {
+				children := t.Children()
+				next = append(next, children[:]...)
+			}
+		}
+		level = next
+	}
+	sort.Slice(tiles, func(i, j int) bool {
+		a, b := tiles[i], tiles[j]
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return tiles
+}
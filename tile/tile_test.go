@@ -0,0 +1,132 @@
+// Copyright 2018 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package tile
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func expect(t testing.TB, what bool) {
+	t.Helper()
+	if !what {
+		t.Fatal("expectation failure")
+	}
+}
+
+func feq(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestTile(t *testing.T) {
+	sf := geometry.Point{X: -122.4194, Y: 37.7749}
+	tile := FromPoint(sf, 10)
+	expect(t, tile == Tile{X: 163, Y: 395, Z: 10})
+	expect(t, tile.String() == "10/163/395")
+	expect(t, tile.Valid())
+	expect(t, tile.Rect().ContainsPoint(sf))
+	expect(t, FromPoint(geometry.Point{X: 180, Y: 90}, 2) == Tile{3, 0, 2})
+	expect(t, FromPoint(geometry.Point{X: -180, Y: -90}, 2) == Tile{0, 3, 2})
+	expect(t, FromPoint(sf, -1) == Tile{})
+
+	rect := Tile{}.Rect()
+	expect(t, rect.Min.X == -180 && rect.Max.X == 180)
+	expect(t, feq(rect.Max.Y, MaxLat) && feq(rect.Min.Y, -MaxLat))
+	expect(t, feq(MaxLat, 85.0511287798066))
+	rect = Tile{X: 1, Y: 0, Z: 1}.Rect()
+	expect(t, rect.Min.X == 0 && feq(rect.Min.Y, 0) && rect.Max.X == 180)
+
+	expect(t, tile.Parent() == Tile{X: 81, Y: 197, Z: 9})
+	expect(t, Tile{}.Parent() == Tile{})
+	for _, child := range tile.Children() {
+		expect(t, child.Parent() == tile)
+		expect(t, tile.Rect().ContainsRect(child.Rect()))
+	}
+	expect(t, tile.Children()[3] == Tile{X: 327, Y: 791, Z: 11})
+
+	expect(t, !Tile{X: 4, Y: 0, Z: 2}.Valid())
+	expect(t, !Tile{X: 0, Y: -1, Z: 2}.Valid())
+	expect(t, !Tile{Z: 31}.Valid())
+}
+
+func TestTileQuadkey(t *testing.T) {
+	expect(t, Tile{X: 3, Y: 5, Z: 3}.Quadkey() == "213")
+	expect(t, Tile{}.Quadkey() == "")
+	tile, ok := FromQuadkey("213")
+	expect(t, ok && tile == Tile{X: 3, Y: 5, Z: 3})
+	tile, ok = FromQuadkey("")
+	expect(t, ok && tile == Tile{})
+	sf := FromPoint(geometry.Point{X: -122.4194, Y: 37.7749}, 17)
+	tile, ok = FromQuadkey(sf.Quadkey())
+	expect(t, ok && tile == sf)
+	_, ok = FromQuadkey("214")
+	expect(t, !ok)
+	_, ok = FromQuadkey("0000000000000000000000000000000")
+	expect(t, !ok)
+}
+
+func TestTileCover(t *testing.T) {
+	point := geojson.NewPoint(geometry.Point{X: -122.4194, Y: 37.7749})
+	tiles := Cover(point, 10)
+	expect(t, len(tiles) == 1 && tiles[0] == Tile{X: 163, Y: 395, Z: 10})
+	tiles = CoverRange(point, 0, 3)
+	expect(t, len(tiles) == 4)
+	for i, tile := range tiles {
+		expect(t, tile.Z == i && tile == FromPoint(point.Center(), i))
+	}
+
+	// a diagonal line is tested with its geometry, and not its rect
+	line := geojson.NewLineString(geometry.NewLine([]geometry.Point{
+		{X: -122.5, Y: 37.7}, {X: -121.5, Y: 38.7},
+	}, nil))
+	tiles = Cover(line, 12)
+	min := FromPoint(geometry.Point{X: -122.5, Y: 38.7}, 12)
+	max := FromPoint(geometry.Point{X: -121.5, Y: 37.7}, 12)
+	bbox := (max.X - min.X + 1) * (max.Y - min.Y + 1)
+	expect(t, len(tiles) > 0 && len(tiles) < bbox/4)
+	for _, tile := range tiles {
+		expect(t, line.Spatial().IntersectsRect(tile.Rect()))
+	}
+	for i := 1; i < len(tiles); i++ {
+		a, b := tiles[i-1], tiles[i]
+		expect(t, a.Y < b.Y || a.Y == b.Y && a.X < b.X)
+	}
+
+	poly := geojson.NewPolygon(geometry.NewPoly([]geometry.Point{
+		{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: 10},
+		{X: -10, Y: -10},
+	}, nil, nil))
+	tiles = CoverRange(poly, 2, 6)
+	var counts [7]int
+	for _, tile := range tiles {
+		counts[tile.Z]++
+	}
+	expect(t, counts[0] == 0 && counts[1] == 0)
+	expect(t, counts[2] == 4 && counts[5] == 4 && counts[6] == 16)
+
+	// the tiles that only touch the edges of the object are not covered
+	tile := Tile{X: 8, Y: 7, Z: 4}
+	tiles = Cover(geojson.NewRect(tile.Rect()), 4)
+	expect(t, len(tiles) == 1 && tiles[0] == tile)
+	tiles = CoverRange(geojson.NewRect(tile.Rect()), 4, 5)
+	expect(t, len(tiles) == 5 && tiles[0] == tile)
+	for _, child := range tiles[1:] {
+		expect(t, child.Parent() == tile)
+	}
+	corner := geometry.Point{X: tile.Rect().Min.X, Y: tile.Rect().Max.Y}
+	tiles = Cover(geojson.NewPoint(corner), 4)
+	expect(t, len(tiles) == 1 && tiles[0] == FromPoint(corner, 4))
+	edge := geojson.NewLineString(geometry.NewLine([]geometry.Point{
+		{X: corner.X, Y: corner.Y - 1}, {X: corner.X, Y: corner.Y - 2},
+	}, nil))
+	tiles = Cover(edge, 4)
+	expect(t, len(tiles) == 1 && tiles[0] == tile)
+
+	expect(t, Cover(geojson.NewGeometryCollection(nil), 3) == nil)
+	expect(t, CoverRange(point, 3, 2) == nil)
+}
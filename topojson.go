@@ -0,0 +1,876 @@
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/pretty"
+)
+
+var (
+	errArcsInvalid      = errors.New("invalid arcs")
+	errObjectsMissing   = errors.New("missing objects")
+	errObjectsInvalid   = errors.New("invalid objects")
+	errTransformInvalid = errors.New("invalid transform")
+)
+
+// topoTransform is the transform of a quantized topology, which converts
+// the integer positions to coordinates.
+type topoTransform struct {
+	scale     geometry.Point
+	translate geometry.Point
+}
+
+func (t *topoTransform) point(x, y float64) geometry.Point {
+	if t == nil {
+		return geometry.Point{X: x, Y: y}
+	}
+	return geometry.Point{
+		X: x*t.scale.X + t.translate.X,
+		Y: y*t.scale.Y + t.translate.Y,
+	}
+}
+
+// topoPoint is a position of a topology, with the Z and M values that follow
+// its first two values.
+type topoPoint struct {
+	point  geometry.Point
+	values []float64
+}
+
+func appendTopoPoint(dst []byte, p topoPoint) []byte {
+	if len(p.values) == 0 {
+		return appendJSONPoint(dst, p.point, nil, 0)
+	}
+	ex := &extra{dims: byte(len(p.values)), values: p.values}
+	return appendJSONPoint(dst, p.point, ex, 0)
+}
+
+// topoReader converts the objects of a topology to GeoJSON.
+type topoReader struct {
+	arcs      [][]topoPoint
+	transform *topoTransform
+}
+
+// ParseTopoJSON parses a TopoJSON topology and returns each of its named
+// objects, with the arcs resolved to coordinates. The third and fourth
+// values of a position are kept as its Z and M coordinates, and are not
+// changed by the transform. A TopoJSON geometry collection becomes a
+// FeatureCollection when any of its geometries have an "id", "properties",
+// or other members, where the geometries without members are Features with
+// null properties, and otherwise a GeometryCollection. A single geometry
+// with members becomes a Feature. Geometries with a null type are left out.
+// The objects are parsed using the provided options.
+func ParseTopoJSON(data string, opts *ParseOptions) (map[string]Object, error) {
+	if opts == nil {
+		opts = DefaultParseOptions
+	}
+	if !gjson.Valid(data) {
+		return nil, errDataInvalid
+	}
+	topo := gjson.Parse(data)
+	if !topo.IsObject() {
+		return nil, errDataInvalid
+	}
+	rType := topo.Get("type")
+	if !rType.Exists() {
+		return nil, errTypeMissing
+	}
+	if rType.Type != gjson.String {
+		return nil, errTypeInvalid
+	}
+	if rType.Str != "Topology" {
+		return nil, fmt.Errorf(fmtErrTypeIsUnknown, rType.Str)
+	}
+	var rd topoReader
+	if rTransform := topo.Get("transform"); rTransform.Exists() {
+		scale, ok1 := topoPosition(rTransform.Get("scale"))
+		translate, ok2 := topoPosition(rTransform.Get("translate"))
+		if !ok1 || !ok2 {
+			return nil, errTransformInvalid
+		}
+		rd.transform = &topoTransform{scale: scale, translate: translate}
+	}
+	rArcs := topo.Get("arcs")
+	if rArcs.Exists() && !rArcs.IsArray() {
+		return nil, errArcsInvalid
+	}
+	var err error
+	rArcs.ForEach(func(_, rArc gjson.Result) bool {
+		var arc []topoPoint
+		arc, err = rd.readArc(rArc)
+		rd.arcs = append(rd.arcs, arc)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rObjects := topo.Get("objects")
+	if !rObjects.Exists() {
+		return nil, errObjectsMissing
+	}
+	if !rObjects.IsObject() {
+		return nil, errObjectsInvalid
+	}
+	objs := make(map[string]Object)
+	var dst []byte
+	rObjects.ForEach(func(key, rObj gjson.Result) bool {
+		dst, err = rd.appendObject(dst[:0], rObj)
+		if err != nil || len(dst) == 0 {
+			return err == nil
+		}
+		var obj Object
+		if obj, err = Parse(string(dst), opts); err != nil {
+			return false
+		}
+		objs[key.String()] = obj
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// topoPosition returns the first two values of a position.
+func topoPosition(rPos gjson.Result) (geometry.Point, bool) {
+	if !rPos.IsArray() {
+		return geometry.Point{}, false
+	}
+	var vals [2]float64
+	var n int
+	rPos.ForEach(func(_, v gjson.Result) bool {
+		if v.Type != gjson.Number {
+			n = -1
+			return false
+		}
+		if n < 2 {
+			vals[n] = v.Num
+		}
+		n++
+		return true
+	})
+	if n < 2 {
+		return geometry.Point{}, false
+	}
+	return geometry.Point{X: vals[0], Y: vals[1]}, true
+}
+
+// topoValues returns the Z and M values of a position, which follow its
+// first two values.
+func topoValues(rPos gjson.Result) []float64 {
+	var values []float64
+	var n int
+	rPos.ForEach(func(_, v gjson.Result) bool {
+		if n >= 2 {
+			values = append(values, v.Num)
+		}
+		n++
+		return n < 4
+	})
+	return values
+}
+
+// readArc reads an arc, which is delta-encoded when the topology has a
+// transform.
+func (rd *topoReader) readArc(rArc gjson.Result) ([]topoPoint, error) {
+	if !rArc.IsArray() {
+		return nil, errArcsInvalid
+	}
+	var arc []topoPoint
+	var x, y float64
+	var ok = true
+	rArc.ForEach(func(_, rPos gjson.Result) bool {
+		var pos geometry.Point
+		if pos, ok = topoPosition(rPos); !ok {
+			return false
+		}
+		if rd.transform != nil {
+			x, y = x+pos.X, y+pos.Y
+			pos = rd.transform.point(x, y)
+		}
+		arc = append(arc, topoPoint{pos, topoValues(rPos)})
+		return true
+	})
+	if !ok || len(arc) == 0 {
+		return nil, errArcsInvalid
+	}
+	return arc, nil
+}
+
+// topoMembers returns the members of a geometry, such as "id" and
+// "properties", as a json object, or an empty string when there are none.
+func topoMembers(rObj gjson.Result) string {
+	var dst []byte
+	rObj.ForEach(func(key, val gjson.Result) bool {
+		switch key.String() {
+		case "type", "arcs", "coordinates", "geometries", "bbox":
+			return true
+		}
+		if len(dst) == 0 {
+			dst = append(dst, '{')
+		} else {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, pretty.Ugly([]byte(key.Raw))...)
+		dst = append(dst, ':')
+		dst = append(dst, pretty.Ugly([]byte(val.Raw))...)
+		return true
+	})
+	if len(dst) == 0 {
+		return ""
+	}
+	return string(append(dst, '}'))
+}
+
+// topoNull returns true for a geometry with a null type.
+func topoNull(rGeom gjson.Result) bool {
+	rType := rGeom.Get("type")
+	return rType.Exists() && rType.Type == gjson.Null
+}
+
+// appendObject appends a named object of the topology as GeoJSON. Nothing is
+// appended for a null geometry.
+func (rd *topoReader) appendObject(dst []byte, rObj gjson.Result) (
+	[]byte, error,
+) {
+	if !rObj.IsObject() {
+		return dst, errObjectsInvalid
+	}
+	if rObj.Get("type").String() != "GeometryCollection" {
+		return rd.appendFeature(dst, rObj, topoMembers(rObj))
+	}
+	rGeoms := rObj.Get("geometries")
+	if !rGeoms.IsArray() {
+		return dst, errGeometriesInvalid
+	}
+	features := false
+	rGeoms.ForEach(func(_, rGeom gjson.Result) bool {
+		features = topoMembers(rGeom) != ""
+		return !features
+	})
+	if features {
+		dst = append(dst, `{"type":"FeatureCollection","features":[`...)
+	} else {
+		dst = append(dst, `{"type":"GeometryCollection","geometries":[`...)
+	}
+	var err error
+	var i int
+	rGeoms.ForEach(func(_, rGeom gjson.Result) bool {
+		if !rGeom.IsObject() {
+			err = errGeometriesInvalid
+			return false
+		}
+		if topoNull(rGeom) {
+			return true
+		}
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		i++
+		members := ""
+		if features {
+			// every geometry of a FeatureCollection is a Feature
+			members = topoMembers(rGeom)
+			if members == "" {
+				members = `{"properties":null}`
+			}
+		}
+		dst, err = rd.appendFeature(dst, rGeom, members)
+		return err == nil
+	})
+	return append(dst, "]}"...), err
+}
+
+// appendFeature appends a geometry, or a Feature when there are members.
+func (rd *topoReader) appendFeature(dst []byte, rGeom gjson.Result,
+	members string,
+) ([]byte, error) {
+	if topoNull(rGeom) {
+		return dst, nil
+	}
+	if members == "" {
+		return rd.appendGeometry(dst, rGeom)
+	}
+	dst = append(dst, `{"type":"Feature","geometry":`...)
+	dst, err := rd.appendGeometry(dst, rGeom)
+	dst = append(dst, ',')
+	dst = append(dst, members[1:]...)
+	return dst, err
+}
+
+// appendGeometry appends a TopoJSON geometry as a GeoJSON geometry.
+func (rd *topoReader) appendGeometry(dst []byte, rGeom gjson.Result) (
+	[]byte, error,
+) {
+	rType := rGeom.Get("type")
+	if !rType.Exists() {
+		return dst, errTypeMissing
+	}
+	if rType.Type != gjson.String {
+		return dst, errTypeInvalid
+	}
+	typ := rType.Str
+	var err error
+	switch typ {
+	case "GeometryCollection":
+		rGeoms := rGeom.Get("geometries")
+		if !rGeoms.IsArray() {
+			return dst, errGeometriesInvalid
+		}
+		dst = append(dst, `{"type":"GeometryCollection","geometries":[`...)
+		var i int
+		rGeoms.ForEach(func(_, rChild gjson.Result) bool {
+			if topoNull(rChild) {
+				return true
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			i++
+			dst, err = rd.appendGeometry(dst, rChild)
+			return err == nil
+		})
+		return append(dst, "]}"...), err
+	case "Point", "MultiPoint":
+		rCoords := rGeom.Get("coordinates")
+		if !rCoords.Exists() {
+			return dst, errCoordinatesMissing
+		}
+		dst = append(dst, `{"type":"`...)
+		dst = append(dst, typ...)
+		dst = append(dst, `","coordinates":`...)
+		if typ == "Point" {
+			dst, err = rd.appendPosition(dst, rCoords)
+		} else {
+			dst, err = rd.appendPositions(dst, rCoords)
+		}
+		return append(dst, '}'), err
+	case "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+		rArcs := rGeom.Get("arcs")
+		if !rArcs.Exists() {
+			return dst, errCoordinatesMissing
+		}
+		depth := 0
+		switch typ {
+		case "MultiLineString", "Polygon":
+			depth = 1
+		case "MultiPolygon":
+			depth = 2
+		}
+		dst = append(dst, `{"type":"`...)
+		dst = append(dst, typ...)
+		dst = append(dst, `","coordinates":`...)
+		dst, err = rd.appendArcs(dst, rArcs, depth)
+		return append(dst, '}'), err
+	default:
+		return dst, fmt.Errorf(fmtErrTypeIsUnknown, typ)
+	}
+}
+
+// appendPosition appends a quantized, but not delta-encoded, position.
+func (rd *topoReader) appendPosition(dst []byte, rPos gjson.Result) (
+	[]byte, error,
+) {
+	pos, ok := topoPosition(rPos)
+	if !ok {
+		return dst, errCoordinatesInvalid
+	}
+	pos = rd.transform.point(pos.X, pos.Y)
+	return appendTopoPoint(dst, topoPoint{pos, topoValues(rPos)}), nil
+}
+
+func (rd *topoReader) appendPositions(dst []byte, rPositions gjson.Result) (
+	[]byte, error,
+) {
+	if !rPositions.IsArray() {
+		return dst, errCoordinatesInvalid
+	}
+	dst = append(dst, '[')
+	var err error
+	var i int
+	rPositions.ForEach(func(_, rPos gjson.Result) bool {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		i++
+		dst, err = rd.appendPosition(dst, rPos)
+		return err == nil
+	})
+	return append(dst, ']'), err
+}
+
+// appendArcs appends the coordinates of the arc indexes, which are nested in
+// arrays to the depth of the geometry type.
+func (rd *topoReader) appendArcs(dst []byte, rArcs gjson.Result, depth int) (
+	[]byte, error,
+) {
+	if !rArcs.IsArray() {
+		return dst, errCoordinatesInvalid
+	}
+	if depth == 0 {
+		var points []topoPoint
+		var ok = true
+		rArcs.ForEach(func(_, rIdx gjson.Result) bool {
+			if rIdx.Type != gjson.Number {
+				ok = false
+				return false
+			}
+			idx := int(rIdx.Int())
+			reverse := idx < 0
+			if reverse {
+				idx = ^idx
+			}
+			if idx >= len(rd.arcs) {
+				ok = false
+				return false
+			}
+			arc := rd.arcs[idx]
+			for i := range arc {
+				j := i
+				if reverse {
+					j = len(arc) - 1 - i
+				}
+				// the first point of each arc is the last point of the
+				// previous arc
+				if i == 0 && len(points) > 0 {
+					continue
+				}
+				points = append(points, arc[j])
+			}
+			return true
+		})
+		if !ok {
+			return dst, errArcsInvalid
+		}
+		dst = append(dst, '[')
+		for i, point := range points {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendTopoPoint(dst, point)
+		}
+		return append(dst, ']'), nil
+	}
+	dst = append(dst, '[')
+	var err error
+	var i int
+	rArcs.ForEach(func(_, rChild gjson.Result) bool {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		i++
+		dst, err = rd.appendArcs(dst, rChild, depth-1)
+		return err == nil
+	})
+	return append(dst, ']'), err
+}
+
+// TopoJSONOptions are the options for AppendTopoJSON.
+type TopoJSONOptions struct {
+	// Name is the name of the object in the topology.
+	// The default is "collection".
+	Name string
+	// Quantization is the number of distinct values for each coordinate,
+	// such as 1e4 or 1e6, which the coordinates are rounded to. The arcs of
+	// a quantized topology are delta-encoded. Setting this value to 0
+	// disables quantization.
+	// The default is 0.
+	Quantization int
+}
+
+// topoWriter converts GeoJSON to a topology with shared arcs.
+type topoWriter struct {
+	quantize bool
+	scale    geometry.Point
+	min      geometry.Point
+	lines    [][]geometry.Point // each line and ring, in the order read
+	rings    []bool
+	lineArcs [][]int // the arc indexes of each line
+	arcs     [][]geometry.Point
+	next     int // next line to write
+}
+
+// AppendTopoJSON appends the object as a TopoJSON topology with a single
+// named object. The lines and rings of the object are cut into arcs at the
+// points where they join, and each shared arc is written once. Features
+// become geometries with their "id", "properties", and other members, but
+// the members of a FeatureCollection are not kept. The positions are written
+// with two values, so any Z and M coordinates are dropped.
+func AppendTopoJSON(dst []byte, obj Object, opts *TopoJSONOptions) []byte {
+	name := "collection"
+	var quantization int
+	if opts != nil {
+		if opts.Name != "" {
+			name = opts.Name
+		}
+		quantization = opts.Quantization
+	}
+	rObj := gjson.Parse(obj.JSON())
+	var w topoWriter
+	rect := obj.Rect()
+	if quantization > 1 {
+		w.quantize = true
+		w.min = rect.Min
+		w.scale.X = (rect.Max.X - rect.Min.X) / float64(quantization-1)
+		w.scale.Y = (rect.Max.Y - rect.Min.Y) / float64(quantization-1)
+		if w.scale.X == 0 {
+			w.scale.X = 1
+		}
+		if w.scale.Y == 0 {
+			w.scale.Y = 1
+		}
+	}
+	w.collect(rObj)
+	w.cut()
+	dst = append(dst, `{"type":"Topology"`...)
+	if !obj.Empty() {
+		dst = append(dst, `,"bbox":[`...)
+		dst = appendJSONFloat(dst, rect.Min.X)
+		dst = append(dst, ',')
+		dst = appendJSONFloat(dst, rect.Min.Y)
+		dst = append(dst, ',')
+		dst = appendJSONFloat(dst, rect.Max.X)
+		dst = append(dst, ',')
+		dst = appendJSONFloat(dst, rect.Max.Y)
+		dst = append(dst, ']')
+	}
+	if w.quantize {
+		dst = append(dst, `,"transform":{"scale":[`...)
+		dst = appendJSONFloat(dst, w.scale.X)
+		dst = append(dst, ',')
+		dst = appendJSONFloat(dst, w.scale.Y)
+		dst = append(dst, `],"translate":[`...)
+		dst = appendJSONFloat(dst, w.min.X)
+		dst = append(dst, ',')
+		dst = appendJSONFloat(dst, w.min.Y)
+		dst = append(dst, "]}"...)
+	}
+	dst = append(dst, `,"objects":{`...)
+	dst = append(dst, pretty.Ugly([]byte(strconv.Quote(name)))...)
+	dst = append(dst, ':')
+	dst = w.appendObject(dst, rObj)
+	dst = append(dst, `},"arcs":[`...)
+	for i, arc := range w.arcs {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = w.appendArc(dst, arc)
+	}
+	return append(dst, "]}"...)
+}
+
+// point returns a coordinate of the object as it is stored in the topology.
+func (w *topoWriter) point(rPos gjson.Result) geometry.Point {
+	pos, _ := topoPosition(rPos)
+	if w.quantize {
+		pos.X = math.Round((pos.X - w.min.X) / w.scale.X)
+		pos.Y = math.Round((pos.Y - w.min.Y) / w.scale.Y)
+	}
+	return pos
+}
+
+// collect reads the lines and rings of every geometry, in the same order
+// that appendObject writes them.
+func (w *topoWriter) collect(rObj gjson.Result) {
+	switch rObj.Get("type").String() {
+	case "FeatureCollection":
+		rObj.Get("features").ForEach(func(_, rChild gjson.Result) bool {
+			w.collect(rChild)
+			return true
+		})
+	case "GeometryCollection":
+		rObj.Get("geometries").ForEach(func(_, rChild gjson.Result) bool {
+			w.collect(rChild)
+			return true
+		})
+	case "Feature":
+		w.collect(rObj.Get("geometry"))
+	case "LineString":
+		w.collectLines(rObj.Get("coordinates"), 0, false)
+	case "MultiLineString":
+		w.collectLines(rObj.Get("coordinates"), 1, false)
+	case "Polygon":
+		w.collectLines(rObj.Get("coordinates"), 1, true)
+	case "MultiPolygon":
+		w.collectLines(rObj.Get("coordinates"), 2, true)
+	}
+}
+
+func (w *topoWriter) collectLines(rCoords gjson.Result, depth int, ring bool) {
+	if depth > 0 {
+		rCoords.ForEach(func(_, rChild gjson.Result) bool {
+			w.collectLines(rChild, depth-1, ring)
+			return true
+		})
+		return
+	}
+	var line []geometry.Point
+	rCoords.ForEach(func(_, rPos gjson.Result) bool {
+		point := w.point(rPos)
+		// quantizing may join neighboring points
+		if len(line) == 0 || line[len(line)-1] != point {
+			line = append(line, point)
+		}
+		return true
+	})
+	if len(line) == 1 {
+		line = append(line, line[0])
+	}
+	if ring && len(line) > 1 && line[0] != line[len(line)-1] {
+		line = append(line, line[0])
+	}
+	w.lines = append(w.lines, line)
+	w.rings = append(w.rings, ring)
+}
+
+// topoNeighbors are the points on each side of a point in a line.
+type topoNeighbors struct {
+	a, b     geometry.Point
+	junction bool
+}
+
+// cut finds the junctions, which are the points where the lines do not share
+// the same neighbors, and cuts the lines into arcs at the junctions. Arcs
+// that are the same in either direction are only added once.
+func (w *topoWriter) cut() {
+	points := make(map[geometry.Point]*topoNeighbors)
+	visit := func(point, a, b geometry.Point, junction bool) {
+		n := points[point]
+		if n == nil {
+			points[point] = &topoNeighbors{a: a, b: b, junction: junction}
+			return
+		}
+		if junction || !(n.a == a && n.b == b || n.a == b && n.b == a) {
+			n.junction = true
+		}
+	}
+	for i, line := range w.lines {
+		if w.rings[i] {
+			n := len(line) - 1
+			for j := 0; j < n; j++ {
+				visit(line[j], line[(j+n-1)%n], line[(j+1)%n], false)
+			}
+		} else {
+			for j := range line {
+				if j == 0 || j == len(line)-1 {
+					visit(line[j], line[j], line[j], true)
+				} else {
+					visit(line[j], line[j-1], line[j+1], false)
+				}
+			}
+		}
+	}
+	isJunction := func(point geometry.Point) bool {
+		return points[point].junction
+	}
+	index := make(map[string]int)
+	w.lineArcs = make([][]int, len(w.lines))
+	for i, line := range w.lines {
+		if w.rings[i] {
+			line = topoRotateRing(line, isJunction)
+		}
+		start := 0
+		for j := 1; j < len(line); j++ {
+			if j == len(line)-1 || isJunction(line[j]) {
+				w.lineArcs[i] = append(w.lineArcs[i],
+					w.addArc(index, line[start:j+1]))
+				start = j
+			}
+		}
+	}
+}
+
+// topoRotateRing rotates a ring to start at its first junction. A ring
+// without junctions starts at its smallest point, so that rings with the same
+// points start at the same point.
+func topoRotateRing(ring []geometry.Point, isJunction func(geometry.Point) bool,
+) []geometry.Point {
+	n := len(ring) - 1
+	start := -1
+	for i := 0; i < n; i++ {
+		if isJunction(ring[i]) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		start = 0
+		for i := 1; i < n; i++ {
+			if ring[i].X < ring[start].X ||
+				ring[i].X == ring[start].X && ring[i].Y < ring[start].Y {
+				start = i
+			}
+		}
+	}
+	if start <= 0 {
+		return ring
+	}
+	rotated := make([]geometry.Point, 0, len(ring))
+	rotated = append(rotated, ring[start:n]...)
+	rotated = append(rotated, ring[:start+1]...)
+	return rotated
+}
+
+// addArc returns the index of an arc, which is negative for an existing arc
+// that is in the reverse direction.
+func (w *topoWriter) addArc(index map[string]int, arc []geometry.Point) int {
+	var key, rkey []byte
+	for i := 0; i < len(arc); i++ {
+		key = appendTopoKey(key, arc[i])
+		rkey = appendTopoKey(rkey, arc[len(arc)-1-i])
+	}
+	if idx, ok := index[string(key)]; ok {
+		return idx
+	}
+	if idx, ok := index[string(rkey)]; ok {
+		return ^idx
+	}
+	idx := len(w.arcs)
+	w.arcs = append(w.arcs, arc)
+	index[string(key)] = idx
+	return idx
+}
+
+func appendTopoKey(dst []byte, point geometry.Point) []byte {
+	x, y := math.Float64bits(point.X), math.Float64bits(point.Y)
+	for i := 0; i < 8; i++ {
+		dst = append(dst, byte(x>>(i*8)))
+	}
+	for i := 0; i < 8; i++ {
+		dst = append(dst, byte(y>>(i*8)))
+	}
+	return dst
+}
+
+// appendArc appends an arc, which is delta-encoded when quantized.
+func (w *topoWriter) appendArc(dst []byte, arc []geometry.Point) []byte {
+	dst = append(dst, '[')
+	var prev geometry.Point
+	for i, point := range arc {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		if w.quantize {
+			dst = append(dst, '[')
+			dst = strconv.AppendInt(dst, int64(point.X-prev.X), 10)
+			dst = append(dst, ',')
+			dst = strconv.AppendInt(dst, int64(point.Y-prev.Y), 10)
+			dst = append(dst, ']')
+			prev = point
+		} else {
+			dst = appendJSONPoint(dst, point, nil, 0)
+		}
+	}
+	return append(dst, ']')
+}
+
+// appendObject appends a GeoJSON object as a TopoJSON geometry.
+func (w *topoWriter) appendObject(dst []byte, rObj gjson.Result) []byte {
+	typ := rObj.Get("type").String()
+	switch typ {
+	case "FeatureCollection", "GeometryCollection":
+		key := "geometries"
+		if typ == "FeatureCollection" {
+			key = "features"
+		}
+		dst = append(dst, `{"type":"GeometryCollection","geometries":[`...)
+		var i int
+		rObj.Get(key).ForEach(func(_, rChild gjson.Result) bool {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			i++
+			dst = w.appendObject(dst, rChild)
+			return true
+		})
+		return append(dst, "]}"...)
+	case "Feature":
+		rGeom := rObj.Get("geometry")
+		if rGeom.Type == gjson.Null {
+			dst = append(dst, `{"type":null`...)
+		} else {
+			dst = w.appendObject(dst, rGeom)
+			dst = dst[:len(dst)-1]
+		}
+		rObj.ForEach(func(key, val gjson.Result) bool {
+			switch key.String() {
+			case "type", "geometry", "bbox":
+				return true
+			}
+			dst = append(dst, ',')
+			dst = append(dst, key.Raw...)
+			dst = append(dst, ':')
+			dst = append(dst, val.Raw...)
+			return true
+		})
+		return append(dst, '}')
+	case "Point", "MultiPoint":
+		dst = append(dst, `{"type":"`...)
+		dst = append(dst, typ...)
+		dst = append(dst, `","coordinates":`...)
+		rCoords := rObj.Get("coordinates")
+		if typ == "Point" {
+			dst = appendJSONPoint(dst, w.point(rCoords), nil, 0)
+		} else {
+			dst = append(dst, '[')
+			var i int
+			rCoords.ForEach(func(_, rPos gjson.Result) bool {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				i++
+				dst = appendJSONPoint(dst, w.point(rPos), nil, 0)
+				return true
+			})
+			dst = append(dst, ']')
+		}
+		return append(dst, '}')
+	case "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+		depth := 0
+		switch typ {
+		case "MultiLineString", "Polygon":
+			depth = 1
+		case "MultiPolygon":
+			depth = 2
+		}
+		dst = append(dst, `{"type":"`...)
+		dst = append(dst, typ...)
+		dst = append(dst, `","arcs":`...)
+		dst = w.appendArcs(dst, rObj.Get("coordinates"), depth)
+		return append(dst, '}')
+	}
+	return append(dst, `{"type":null}`...)
+}
+
+// appendArcs appends the arc indexes of the lines, in the same nesting as
+// the coordinates.
+func (w *topoWriter) appendArcs(dst []byte, rCoords gjson.Result, depth int,
+) []byte {
+	if depth == 0 {
+		dst = append(dst, '[')
+		for i, idx := range w.lineArcs[w.next] {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = strconv.AppendInt(dst, int64(idx), 10)
+		}
+		w.next++
+		return append(dst, ']')
+	}
+	dst = append(dst, '[')
+	var i int
+	rCoords.ForEach(func(_, rChild gjson.Result) bool {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		i++
+		dst = w.appendArcs(dst, rChild, depth-1)
+		return true
+	})
+	return append(dst, ']')
+}
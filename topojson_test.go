@@ -0,0 +1,269 @@
+package geojson
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/gjson"
+)
+
+func expectPoints(t *testing.T, points []geometry.Point, expect ...float64) {
+	t.Helper()
+	if len(points)*2 != len(expect) {
+		t.Fatalf("expected %d points, got %v", len(expect)/2, points)
+	}
+	for i, point := range points {
+		if math.Abs(point.X-expect[i*2]) > 1e-9 ||
+			math.Abs(point.Y-expect[i*2+1]) > 1e-9 {
+			t.Fatalf("expected %v, got %v", expect, points)
+		}
+	}
+}
+
+func TestTopoJSONParse(t *testing.T) {
+	// the example from the TopoJSON specification
+	topo := `{
+		"type": "Topology",
+		"transform": {"scale": [0.0005, 0.0001], "translate": [100, 0]},
+		"objects": {
+			"example": {
+				"type": "GeometryCollection",
+				"geometries": [
+					{"type": "Point", "properties": {"prop0": "value0"},
+					 "coordinates": [4000, 5000]},
+					{"type": "LineString",
+					 "properties": {"prop0": "value0", "prop1": 0},
+					 "arcs": [0]},
+					{"type": "Polygon",
+					 "properties": {"prop0": "value0", "prop1": {"this": "that"}},
+					 "arcs": [[-2]]},
+					{"type": null, "properties": {}}
+				]
+			},
+			"line": {"type": "MultiLineString", "arcs": [[0], [1, -2]]},
+			"empty": {"type": null}
+		},
+		"arcs": [
+			[[4000, 0], [1999, 9999], [2000, -9999], [2000, 9999]],
+			[[0, 0], [0, 9999], [2000, 0], [0, -9999], [-2000, 0]]
+		]
+	}`
+	objs, err := ParseTopoJSON(topo, nil)
+	expect(t, err == nil)
+	expect(t, len(objs) == 2)
+	fc, ok := objs["example"].(*FeatureCollection)
+	expect(t, ok)
+	children := fc.Children()
+	expect(t, len(children) == 3)
+	expect(t, children[0].Members() == `{"properties":{"prop0":"value0"}}`)
+	expect(t, children[0].Center() == P(102, 0.5))
+	line := children[1].(*Feature).Base().(*LineString).base
+	expectPoints(t, seriesPoints(&line), 102, 0, 102.9995, 0.9999, 103.9995, 0,
+		104.9995, 0.9999)
+	poly := children[2].(*Feature).Base().(*Polygon).base
+	expectPoints(t, seriesPoints(poly.Exterior), 100, 0, 101, 0, 101, 0.9999,
+		100, 0.9999, 100, 0)
+	expect(t, gjson.Get(children[2].Members(), "properties.prop1.this").String() ==
+		"that")
+
+	mls, ok := objs["line"].(*MultiLineString)
+	expect(t, ok)
+	expect(t, len(mls.Children()) == 2)
+	// the second line is an arc and its reverse, without repeating the point
+	expect(t, mls.Children()[1].NumPoints() == 9)
+
+	// without a transform the arcs are coordinates
+	objs, err = ParseTopoJSON(`{"type":"Topology","objects":{
+		"a":{"type":"GeometryCollection","geometries":[
+			{"type":"LineString","arcs":[0]},
+			{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}
+		]}},"arcs":[[[1.5,2.5],[3.5,4.5]]]}`, nil)
+	expect(t, err == nil)
+	expect(t, objs["a"].JSON() == `{"type":"GeometryCollection","geometries":[`+
+		`{"type":"LineString","coordinates":[[1.5,2.5],[3.5,4.5]]},`+
+		`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}]}`)
+
+	// parse options
+	opts := *DefaultParseOptions
+	opts.AllowSimplePoints = true
+	objs, err = ParseTopoJSON(`{"type":"Topology","objects":{
+		"a":{"type":"Point","coordinates":[1,2]}},"arcs":[]}`, &opts)
+	expect(t, err == nil)
+	_, ok = objs["a"].(*SimplePoint)
+	expect(t, ok)
+}
+
+func TestTopoJSONMixedMembers(t *testing.T) {
+	// a geometry without members is still a Feature in a FeatureCollection
+	objs, err := ParseTopoJSON(`{"type":"Topology","objects":{
+		"a":{"type":"GeometryCollection","geometries":[
+			{"type":"Point","coordinates":[1,2],"properties":{"name":"p"}},
+			{"type":"Point","coordinates":[3,4]}
+		]}},"arcs":[]}`, nil)
+	expect(t, err == nil)
+	expect(t, objs["a"].JSON() == `{"type":"FeatureCollection","features":[`+
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},`+
+		`"properties":{"name":"p"}},`+
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4]},`+
+		`"properties":null}]}`)
+	for _, child := range objs["a"].(*FeatureCollection).Children() {
+		_, ok := child.(*Feature)
+		expect(t, ok)
+	}
+}
+
+func TestTopoJSONZ(t *testing.T) {
+	// the third and fourth values of a position are not transformed
+	objs, err := ParseTopoJSON(`{"type":"Topology",
+		"transform":{"scale":[0.5,0.5],"translate":[10,20]},
+		"objects":{
+			"line":{"type":"LineString","arcs":[0,-2]},
+			"point":{"type":"Point","coordinates":[2,4,7,8]}
+		},
+		"arcs":[[[0,0,5],[2,2,6]],[[4,0,9],[-2,2,6]]]}`, nil)
+	expect(t, err == nil)
+	expect(t, objs["line"].JSON() == `{"type":"LineString","coordinates":`+
+		`[[10,20,5],[11,21,6],[12,20,9]]}`)
+	expect(t, objs["point"].JSON() ==
+		`{"type":"Point","coordinates":[11,22,7,8]}`)
+
+	// the arcs of a written topology only have two values
+	topo := string(AppendTopoJSON(nil, objs["line"], nil))
+	expect(t, gjson.Get(topo, "arcs").Raw == `[[[10,20],[11,21],[12,20]]]`)
+	objs, err = ParseTopoJSON(topo, nil)
+	expect(t, err == nil)
+	expect(t, objs["collection"].JSON() == `{"type":"LineString",`+
+		`"coordinates":[[10,20],[11,21],[12,20]]}`)
+}
+
+func TestTopoJSONParseErrors(t *testing.T) {
+	parseErr := func(topo string) error {
+		t.Helper()
+		_, err := ParseTopoJSON(topo, nil)
+		return err
+	}
+	expect(t, parseErr(`{`) == errDataInvalid)
+	expect(t, parseErr(`[]`) == errDataInvalid)
+	expect(t, parseErr(`{"objects":{}}`) == errTypeMissing)
+	expect(t, parseErr(`{"type":1}`) == errTypeInvalid)
+	expect(t, parseErr(`{"type":"FeatureCollection"}`).Error() ==
+		"type 'FeatureCollection' is unknown")
+	expect(t, parseErr(`{"type":"Topology"}`) == errObjectsMissing)
+	expect(t, parseErr(`{"type":"Topology","objects":[]}`) == errObjectsInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{},"arcs":{}}`) ==
+		errArcsInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{},"arcs":[[[1]]]}`) ==
+		errArcsInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{},"arcs":[[]]}`) ==
+		errArcsInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{},`+
+		`"transform":{"scale":[1,1]}}`) == errTransformInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"type":"LineString","arcs":[1]}},"arcs":[[[0,0],[1,1]]]}`) ==
+		errArcsInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"type":"LineString","arcs":[-3]}},"arcs":[[[0,0],[1,1]]]}`) ==
+		errArcsInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"type":"LineString"}}}`) == errCoordinatesMissing)
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"type":"Point","coordinates":[1]}}}`) == errCoordinatesInvalid)
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"type":"Circle"}}}`).Error() == "type 'Circle' is unknown")
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"coordinates":[1,2]}}}`) == errTypeMissing)
+	expect(t, parseErr(`{"type":"Topology","objects":{`+
+		`"a":{"type":"GeometryCollection","geometries":{}}}}`) ==
+		errGeometriesInvalid)
+}
+
+func TestTopoJSONAppend(t *testing.T) {
+	// two squares that share an edge
+	fc := expectJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":"a","geometry":{"type":"Polygon",
+		 "coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]},"properties":{}},
+		{"type":"Feature","id":"b","geometry":{"type":"Polygon",
+		 "coordinates":[[[1,0],[2,0],[2,1],[1,1],[1,0]]]},
+		 "properties":{"name":"b"}},
+		{"type":"Feature","geometry":{"type":"LineString",
+		 "coordinates":[[0,0],[1,0],[2,0]]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Point",
+		 "coordinates":[0.5,0.5]},"properties":{}}
+	]}`, nil)
+	topo := string(AppendTopoJSON(nil, fc, &TopoJSONOptions{Name: "squares"}))
+	expect(t, gjson.Get(topo, "bbox").Raw == `[0,0,2,1]`)
+	expect(t, !gjson.Get(topo, "transform").Exists())
+	// the shared edge and the line segments are each written once
+	expect(t, gjson.Get(topo, "arcs.#").Int() == 5)
+	expect(t, gjson.Get(topo, "objects.squares.geometries.0.id").String() == "a")
+	expect(t, gjson.Get(topo,
+		"objects.squares.geometries.1.properties.name").String() == "b")
+	expect(t, gjson.Get(topo, "objects.squares.geometries.3.coordinates").Raw ==
+		"[0.5,0.5]")
+	objs, err := ParseTopoJSON(topo, nil)
+	expect(t, err == nil)
+	children := objs["squares"].(*FeatureCollection).Children()
+	expect(t, len(children) == 4)
+	for i, child := range children {
+		orig := fc.(*FeatureCollection).Children()[i]
+		expect(t, child.Members() == orig.Members())
+		expect(t, child.Rect() == orig.Rect())
+		expect(t, child.NumPoints() == orig.NumPoints())
+		expect(t, child.Contains(orig) && orig.Contains(child))
+	}
+
+	// quantized
+	topo = string(AppendTopoJSON(nil, fc, &TopoJSONOptions{Quantization: 1e4}))
+	expect(t, gjson.Get(topo, "transform.translate").Raw == `[0,0]`)
+	expect(t, gjson.Get(topo, "objects.collection.geometries.3.coordinates").Raw ==
+		"[2500,5000]")
+	objs, err = ParseTopoJSON(topo, nil)
+	expect(t, err == nil)
+	children = objs["collection"].(*FeatureCollection).Children()
+	for i, child := range children {
+		orig := fc.(*FeatureCollection).Children()[i]
+		expect(t, child.NumPoints() == orig.NumPoints())
+		expect(t, math.Abs(child.Rect().Max.X-orig.Rect().Max.X) < 1e-3)
+	}
+
+	// a hole that is also an island, and a geometry collection
+	gc := expectJSON(t, `{"type":"GeometryCollection","geometries":[
+		{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],
+		 [[2,2],[2,4],[4,4],[4,2],[2,2]]]},
+		{"type":"Polygon","coordinates":[[[4,4],[2,4],[2,2],[4,2],[4,4]]]},
+		{"type":"MultiPoint","coordinates":[[1,1],[2,2]]}
+	]}`, nil)
+	topo = string(AppendTopoJSON(nil, gc, nil))
+	expect(t, gjson.Get(topo, "arcs.#").Int() == 2)
+	expect(t, gjson.Get(topo, "objects.collection.geometries.1.arcs").Raw ==
+		"[[-2]]")
+	objs, err = ParseTopoJSON(topo, nil)
+	expect(t, err == nil)
+	children = objs["collection"].(*GeometryCollection).Children()
+	for i, child := range children {
+		orig := gc.(*GeometryCollection).Children()[i]
+		expect(t, child.NumPoints() == orig.NumPoints())
+		expect(t, child.Contains(orig) && orig.Contains(child))
+	}
+}
+
+func TestTopoJSONBoston(t *testing.T) {
+	data, err := ioutil.ReadFile("test_files/boston_subset.geojson")
+	expect(t, err == nil)
+	fc := parseCollection(t, string(data), false).(Object)
+	topo := AppendTopoJSON(nil, fc, nil)
+	objs, err := ParseTopoJSON(string(topo), nil)
+	expect(t, err == nil)
+	children := objs["collection"].(Collection).Children()
+	expect(t, len(children) == len(fc.(Collection).Children()))
+	for i, child := range children {
+		orig := fc.(Collection).Children()[i]
+		expect(t, child.Rect() == orig.Rect())
+		expect(t, child.NumPoints() == orig.NumPoints())
+	}
+	topo = AppendTopoJSON(nil, fc, &TopoJSONOptions{Quantization: 1e6})
+	_, err = ParseTopoJSON(string(topo), nil)
+	expect(t, err == nil)
+}
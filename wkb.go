@@ -0,0 +1,507 @@
+package geojson
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// Well-known binary geometry types
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// PostGIS extended well-known binary flags
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// wkbReader reads well-known binary from a string.
+type wkbReader struct {
+	data string
+	pos  int
+	opts *ParseOptions
+}
+
+// parseWKB parses well-known binary, or PostGIS extended well-known binary.
+// Any SRID is read and ignored.
+func parseWKB(data string, opts *ParseOptions) (Object, error) {
+	rd := &wkbReader{data: data, opts: opts}
+	o, err := rd.readGeometry(0)
+	if err != nil {
+		return nil, err
+	}
+	if rd.pos != len(rd.data) {
+		return nil, errDataInvalid
+	}
+	return o, nil
+}
+
+// wkbHeader is the byte order and type that begins every geometry.
+type wkbHeader struct {
+	big  bool   // big endian byte order
+	typ  uint32 // base geometry type, 1 through 7
+	dims int    // number of extra coordinate values
+	m    bool   // has an M coordinate
+}
+
+func (rd *wkbReader) readUint32(big bool) (uint32, bool) {
+	if len(rd.data)-rd.pos < 4 {
+		return 0, false
+	}
+	b := rd.data[rd.pos : rd.pos+4]
+	rd.pos += 4
+	if big {
+		return uint32(b[3]) | uint32(b[2])<<8 | uint32(b[1])<<16 |
+			uint32(b[0])<<24, true
+	}
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 |
+		uint32(b[3])<<24, true
+}
+
+func (rd *wkbReader) readFloat64(big bool) (float64, bool) {
+	if len(rd.data)-rd.pos < 8 {
+		return 0, false
+	}
+	b := rd.data[rd.pos : rd.pos+8]
+	rd.pos += 8
+	var bits uint64
+	for i := 0; i < 8; i++ {
+		if big {
+			bits = bits<<8 | uint64(b[i])
+		} else {
+			bits = bits<<8 | uint64(b[7-i])
+		}
+	}
+	return math.Float64frombits(bits), true
+}
+
+// readCount reads the number of items that follow, making sure that there's
+// enough data for each item to be at least size bytes.
+func (rd *wkbReader) readCount(big bool, size int) (int, bool) {
+	n, ok := rd.readUint32(big)
+	if !ok || uint64(n)*uint64(size) > uint64(len(rd.data)-rd.pos) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+func (rd *wkbReader) readHeader() (wkbHeader, error) {
+	var hdr wkbHeader
+	if rd.pos == len(rd.data) {
+		return hdr, errDataInvalid
+	}
+	switch rd.data[rd.pos] {
+	case 0:
+		hdr.big = true
+	case 1:
+	default:
+		return hdr, errDataInvalid
+	}
+	rd.pos++
+	typ, ok := rd.readUint32(hdr.big)
+	if !ok {
+		return hdr, errDataInvalid
+	}
+	// PostGIS extended flags
+	hasZ := typ&ewkbZ != 0
+	hdr.m = typ&ewkbM != 0
+	if typ&ewkbSRID != 0 {
+		if _, ok := rd.readUint32(hdr.big); !ok {
+			return hdr, errDataInvalid
+		}
+	}
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	// ISO dimension offsets
+	switch typ / 1000 {
+	case 0:
+	case 1:
+		hasZ = true
+	case 2:
+		hdr.m = true
+	case 3:
+		hasZ, hdr.m = true, true
+	default:
+		return hdr, errDataInvalid
+	}
+	hdr.typ = typ % 1000
+	if hasZ {
+		hdr.dims++
+	}
+	if hdr.m {
+		hdr.dims++
+	}
+	return hdr, nil
+}
+
+// readPoint reads a single coordinate and appends any Z/M values to ex.
+func (rd *wkbReader) readPoint(hdr wkbHeader, ex **extra) (
+	geometry.Point, error,
+) {
+	var nums [4]float64
+	for i := 0; i < 2+hdr.dims; i++ {
+		var ok bool
+		if nums[i], ok = rd.readFloat64(hdr.big); !ok {
+			return geometry.Point{}, errCoordinatesInvalid
+		}
+	}
+	if hdr.dims > 0 {
+		if *ex == nil {
+			*ex = new(extra)
+			(*ex).dims = byte(hdr.dims)
+			(*ex).measure = hdr.m && hdr.dims == 1
+		}
+		(*ex).values = append((*ex).values, nums[2:2+hdr.dims]...)
+	}
+	return geometry.Point{X: nums[0], Y: nums[1]}, nil
+}
+
+func (rd *wkbReader) readPoints(hdr wkbHeader, ex **extra) (
+	[]geometry.Point, error,
+) {
+	n, ok := rd.readCount(hdr.big, 8*(2+hdr.dims))
+	if !ok {
+		return nil, errCoordinatesInvalid
+	}
+	points := make([]geometry.Point, n)
+	for i := 0; i < n; i++ {
+		var err error
+		if points[i], err = rd.readPoint(hdr, ex); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (rd *wkbReader) readRings(hdr wkbHeader, ex **extra) (
+	[][]geometry.Point, error,
+) {
+	n, ok := rd.readCount(hdr.big, 4)
+	if !ok {
+		return nil, errCoordinatesInvalid
+	}
+	rings := make([][]geometry.Point, n)
+	for i := 0; i < n; i++ {
+		ring, err := rd.readPoints(hdr, ex)
+		if err != nil {
+			return nil, err
+		}
+		if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+			return nil, errCoordinatesInvalid // must be a linear ring
+		}
+		rings[i] = ring
+	}
+	return rings, nil
+}
+
+// readGeometry reads a geometry. When a parent type is provided, such as
+// wkbMultiPoint, the geometry must be a valid member of the parent.
+func (rd *wkbReader) readGeometry(parent uint32) (Object, error) {
+	hdr, err := rd.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	switch parent {
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon:
+		if hdr.typ != parent-3 {
+			return nil, errTypeInvalid
+		}
+	}
+	switch hdr.typ {
+	default:
+		return nil, errTypeInvalid
+	case wkbPoint:
+		return rd.readPointBody(hdr)
+	case wkbLineString:
+		return rd.readLineStringBody(hdr)
+	case wkbPolygon:
+		return rd.readPolygonBody(hdr)
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon,
+		wkbGeometryCollection:
+		return rd.readCollectionBody(hdr)
+	}
+}
+
+func (rd *wkbReader) readPointBody(hdr wkbHeader) (Object, error) {
+	var o Object
+	var ex *extra
+	point, err := rd.readPoint(hdr, &ex)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(point.X) && math.IsNaN(point.Y) {
+		// NaN coordinates are an empty point
+		return newEmptyPoint(ex), nil
+	}
+	if ex == nil && rd.opts.AllowSimplePoints {
+		o = &SimplePoint{Point: point}
+	} else {
+		o = &Point{base: point, extra: ex}
+	}
+	if rd.opts.RequireValid {
+		if !o.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	return o, nil
+}
+
+func (rd *wkbReader) readLineStringBody(hdr wkbHeader) (Object, error) {
+	var ex *extra
+	points, err := rd.readPoints(hdr, &ex)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 1 {
+		return nil, errCoordinatesInvalid
+	}
+	gopts := toGeometryOpts(rd.opts)
+	g := &LineString{base: *geometry.NewLine(points, &gopts), extra: ex}
+	if rd.opts.RequireValid {
+		if !g.Valid() {
+			return nil, errDataInvalid
+		}
+	}
+	return g, nil
+}
+
+func (rd *wkbReader) readPolygonBody(hdr wkbHeader) (Object, error) {
+	var ex *extra
+	rings, err := rd.readRings(hdr, &ex)
+	if err != nil {
+		return nil, err
+	}
+	if len(rings) == 0 {
+		gopts := toGeometryOpts(rd.opts)
+		return &Polygon{base: *geometry.NewPoly(nil, nil, &gopts)}, nil
+	}
+	o := parsePolygonObject(rings, ex, rd.opts)
+	if rd.opts.RequireValid {
+		if !o.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	return o, nil
+}
+
+func (rd *wkbReader) readCollectionBody(hdr wkbHeader) (Object, error) {
+	// each child is at least a byte order and a type
+	n, ok := rd.readCount(hdr.big, 5)
+	if !ok {
+		return nil, errDataInvalid
+	}
+	var c collection
+	for i := 0; i < n; i++ {
+		child, err := rd.readGeometry(hdr.typ)
+		if err != nil {
+			return nil, err
+		}
+		if hdr.typ == wkbMultiPoint && math.IsNaN(child.Center().X) {
+			// ignore empty points
+			continue
+		}
+		c.children = append(c.children, child)
+	}
+	if rd.opts.RequireValid {
+		for _, child := range c.children {
+			if !child.Valid() {
+				return nil, errCoordinatesInvalid
+			}
+		}
+	}
+	c.parseInitRectIndex(rd.opts)
+	switch hdr.typ {
+	case wkbMultiPoint:
+		return &MultiPoint{c}, nil
+	case wkbMultiLineString:
+		return &MultiLineString{c}, nil
+	case wkbMultiPolygon:
+		return &MultiPolygon{c}, nil
+	default:
+		return &GeometryCollection{c}, nil
+	}
+}
+
+// WKBOptions are options for encoding well-known binary.
+type WKBOptions struct {
+	// BigEndian will encode using the big endian (XDR) byte order. The
+	// default is little endian (NDR).
+	BigEndian bool
+	// SRID will encode PostGIS extended well-known binary (EWKB) that
+	// includes the spatial reference identifier, such as 4326, when the
+	// value is not zero.
+	SRID uint32
+	// members of an EWKB collection use the EWKB flags without an SRID
+	extended bool
+}
+
+// childWKBOptions returns the options for the members of a collection, which
+// never include an SRID.
+func childWKBOptions(opts *WKBOptions) *WKBOptions {
+	if opts == nil || opts.SRID == 0 {
+		return opts
+	}
+	copts := *opts
+	copts.SRID = 0
+	copts.extended = true
+	return &copts
+}
+
+func appendWKBUint32(dst []byte, opts *WKBOptions, n uint32) []byte {
+	if opts != nil && opts.BigEndian {
+		return append(dst, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(dst, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+}
+
+func appendWKBFloat64(dst []byte, opts *WKBOptions, f float64) []byte {
+	bits := math.Float64bits(f)
+	for i := 0; i < 8; i++ {
+		if opts != nil && opts.BigEndian {
+			dst = append(dst, byte(bits>>(56-8*i)))
+		} else {
+			dst = append(dst, byte(bits>>(8*i)))
+		}
+	}
+	return dst
+}
+
+// appendWKBHeader appends the byte order and geometry type. The type uses
+// the ISO dimension offsets, or the EWKB flags when there is an SRID.
+func appendWKBHeader(
+	dst []byte, opts *WKBOptions, typ uint32, dims int, measure bool,
+) []byte {
+	if opts != nil && opts.BigEndian {
+		dst = append(dst, 0)
+	} else {
+		dst = append(dst, 1)
+	}
+	hasZ := dims == 2 || (dims == 1 && !measure)
+	hasM := dims == 2 || (dims == 1 && measure)
+	if opts != nil && (opts.SRID != 0 || opts.extended) {
+		if hasZ {
+			typ |= ewkbZ
+		}
+		if hasM {
+			typ |= ewkbM
+		}
+		if opts.SRID == 0 {
+			return appendWKBUint32(dst, opts, typ)
+		}
+		dst = appendWKBUint32(dst, opts, typ|ewkbSRID)
+		return appendWKBUint32(dst, opts, opts.SRID)
+	}
+	if hasZ {
+		typ += 1000
+	}
+	if hasM {
+		typ += 2000
+	}
+	return appendWKBUint32(dst, opts, typ)
+}
+
+// appendWKBPoint appends a single coordinate with dims extra values. The
+// values are read from ex at point index idx, or zero when not available.
+func appendWKBPoint(
+	dst []byte, opts *WKBOptions, point geometry.Point, ex *extra, idx, dims int,
+) []byte {
+	dst = appendWKBFloat64(dst, opts, point.X)
+	dst = appendWKBFloat64(dst, opts, point.Y)
+	for i := 0; i < dims; i++ {
+		var v float64
+		if ex != nil && i < int(ex.dims) {
+			v = ex.values[idx*int(ex.dims)+i]
+		}
+		dst = appendWKBFloat64(dst, opts, v)
+	}
+	return dst
+}
+
+func appendWKBSeries(
+	dst []byte, opts *WKBOptions, series geometry.Series, ex *extra,
+	pidx, dims int,
+) (ndst []byte, npidx int) {
+	nPoints := series.NumPoints()
+	dst = appendWKBUint32(dst, opts, uint32(nPoints))
+	for i := 0; i < nPoints; i++ {
+		dst = appendWKBPoint(dst, opts, series.PointAt(i), ex, pidx, dims)
+		pidx++
+	}
+	return dst, pidx
+}
+
+func appendWKBPoly(
+	dst []byte, opts *WKBOptions, poly *geometry.Poly, ex *extra, dims int,
+) []byte {
+	if poly.Empty() {
+		return appendWKBUint32(dst, opts, 0)
+	}
+	var pidx int
+	dst = appendWKBUint32(dst, opts, uint32(1+len(poly.Holes)))
+	dst, pidx = appendWKBSeries(dst, opts, poly.Exterior, ex, pidx, dims)
+	for _, hole := range poly.Holes {
+		dst, pidx = appendWKBSeries(dst, opts, hole, ex, pidx, dims)
+	}
+	return dst
+}
+
+// appendWKBObject appends a Point, LineString, Polygon, or Rect using dims
+// extra coordinate values.
+func appendWKBObject(
+	dst []byte, opts *WKBOptions, obj Object, dims int, measure bool,
+) []byte {
+	switch obj := obj.(type) {
+	case *Point:
+		dst = appendWKBHeader(dst, opts, wkbPoint, dims, measure)
+		return appendWKBPoint(dst, opts, obj.base, obj.extra, 0, dims)
+	case *SimplePoint:
+		dst = appendWKBHeader(dst, opts, wkbPoint, dims, measure)
+		return appendWKBPoint(dst, opts, obj.Point, nil, 0, dims)
+	case *LineString:
+		dst = appendWKBHeader(dst, opts, wkbLineString, dims, measure)
+		dst, _ = appendWKBSeries(dst, opts, &obj.base, obj.extra, 0, dims)
+		return dst
+	case *Polygon:
+		dst = appendWKBHeader(dst, opts, wkbPolygon, dims, measure)
+		return appendWKBPoly(dst, opts, &obj.base, obj.extra, dims)
+	case *Rect:
+		return appendWKBObject(dst, opts, obj.Polygon(), dims, measure)
+	}
+	return obj.AppendWKB(dst, opts)
+}
+
+// appendWKBChildren appends a Multi* collection of type typ, where each child
+// uses the largest coordinate dimensions of all children.
+func appendWKBChildren(
+	dst []byte, opts *WKBOptions, typ uint32, children []Object,
+) []byte {
+	dims, measure := childrenDims(children)
+	dst = appendWKBHeader(dst, opts, typ, dims, measure)
+	dst = appendWKBUint32(dst, opts, uint32(len(children)))
+	copts := childWKBOptions(opts)
+	for _, child := range children {
+		dst = appendWKBObject(dst, copts, child, dims, measure)
+	}
+	return dst
+}
+
+// appendWKBGeometries appends a GeometryCollection of objects.
+func appendWKBGeometries(
+	dst []byte, opts *WKBOptions, objs []Object,
+) []byte {
+	dst = appendWKBHeader(dst, opts, wkbGeometryCollection, 0, false)
+	dst = appendWKBUint32(dst, opts, uint32(len(objs)))
+	copts := childWKBOptions(opts)
+	for _, obj := range objs {
+		dst = obj.AppendWKB(dst, copts)
+	}
+	return dst
+}
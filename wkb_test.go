@@ -0,0 +1,235 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// wkbBuilder builds well-known binary for tests.
+type wkbBuilder struct {
+	order binary.ByteOrder
+	data  []byte
+}
+
+func newWKBBuilder(big bool) *wkbBuilder {
+	if big {
+		return &wkbBuilder{order: binary.BigEndian}
+	}
+	return &wkbBuilder{order: binary.LittleEndian}
+}
+
+func (b *wkbBuilder) header(typ uint32) *wkbBuilder {
+	if b.order == binary.BigEndian {
+		b.data = append(b.data, 0)
+	} else {
+		b.data = append(b.data, 1)
+	}
+	return b.uint32(typ)
+}
+
+func (b *wkbBuilder) uint32(n uint32) *wkbBuilder {
+	var buf [4]byte
+	b.order.PutUint32(buf[:], n)
+	b.data = append(b.data, buf[:]...)
+	return b
+}
+
+func (b *wkbBuilder) floats(fs ...float64) *wkbBuilder {
+	for _, f := range fs {
+		var buf [8]byte
+		b.order.PutUint64(buf[:], math.Float64bits(f))
+		b.data = append(b.data, buf[:]...)
+	}
+	return b
+}
+
+func (b *wkbBuilder) String() string {
+	return string(b.data)
+}
+
+func TestWKBParse(t *testing.T) {
+	for _, big := range []bool{false, true} {
+		expectJSON(t, newWKBBuilder(big).header(1).floats(1, 2).String(),
+			`{"type":"Point","coordinates":[1,2]}`)
+		expectJSON(t, newWKBBuilder(big).header(1001).floats(1, 2, 3).String(),
+			`{"type":"Point","coordinates":[1,2,3]}`)
+		expectJSON(t, newWKBBuilder(big).header(3001).floats(1, 2, 3, 4).String(),
+			`{"type":"Point","coordinates":[1,2,3,4]}`)
+		expectJSON(t, newWKBBuilder(big).header(2).uint32(2).
+			floats(1, 2, 3, 4).String(),
+			`{"type":"LineString","coordinates":[[1,2],[3,4]]}`)
+		expectJSON(t, newWKBBuilder(big).header(3).uint32(1).uint32(4).
+			floats(0, 0, 10, 0, 10, 10, 0, 0).String(),
+			`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,0]]]}`)
+		b := newWKBBuilder(big).header(4).uint32(2)
+		b.header(1).floats(1, 2)
+		b.header(1).floats(3, 4)
+		expectJSON(t, b.String(),
+			`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`)
+		b = newWKBBuilder(big).header(5).uint32(1)
+		b.header(2).uint32(2).floats(1, 2, 3, 4)
+		expectJSON(t, b.String(),
+			`{"type":"MultiLineString","coordinates":[[[1,2],[3,4]]]}`)
+		b = newWKBBuilder(big).header(6).uint32(1)
+		b.header(3).uint32(1).uint32(4).floats(0, 0, 10, 0, 10, 10, 0, 0)
+		expectJSON(t, b.String(),
+			`{"type":"MultiPolygon","coordinates":[[[[0,0],[10,0],[10,10],[0,0]]]]}`)
+		b = newWKBBuilder(big).header(7).uint32(2)
+		b.header(1).floats(1, 2)
+		b.header(2).uint32(2).floats(1, 2, 3, 4)
+		expectJSON(t, b.String(),
+			`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"LineString","coordinates":[[1,2],[3,4]]}]}`)
+	}
+}
+
+func TestWKBParseMixedByteOrder(t *testing.T) {
+	b := newWKBBuilder(true).header(4).uint32(2)
+	b.header(1).floats(1, 2)
+	little := newWKBBuilder(false).header(1).floats(3, 4)
+	b.data = append(b.data, little.data...)
+	expectJSON(t, b.String(),
+		`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`)
+}
+
+func TestWKBParseEWKB(t *testing.T) {
+	// SRID=4326;POINT(1 2)
+	expectJSON(t, newWKBBuilder(false).header(1|ewkbSRID).uint32(4326).
+		floats(1, 2).String(),
+		`{"type":"Point","coordinates":[1,2]}`)
+	// SRID=4326;LINESTRING Z (1 2 3,4 5 6)
+	expectJSON(t, newWKBBuilder(true).header(2|ewkbZ|ewkbSRID).uint32(4326).
+		uint32(2).floats(1, 2, 3, 4, 5, 6).String(),
+		`{"type":"LineString","coordinates":[[1,2,3],[4,5,6]]}`)
+	// POINT ZM (1 2 3 4)
+	expectJSON(t, newWKBBuilder(false).header(1|ewkbZ|ewkbM).
+		floats(1, 2, 3, 4).String(),
+		`{"type":"Point","coordinates":[1,2,3,4]}`)
+	// POINT M (1 2 3)
+	g := expectJSON(t, newWKBBuilder(false).header(1|ewkbM).
+		floats(1, 2, 3).String(),
+		`{"type":"Point","coordinates":[1,2,3]}`)
+	expect(t, g.(*Point).extra.measure)
+	g = expectJSON(t, newWKBBuilder(false).header(2001).
+		floats(1, 2, 3).String(),
+		`{"type":"Point","coordinates":[1,2,3]}`)
+	expect(t, g.(*Point).extra.measure)
+}
+
+func TestWKBParseEmpty(t *testing.T) {
+	g := expectJSON(t, newWKBBuilder(false).header(2).uint32(0).String(),
+		`{"type":"LineString","coordinates":[]}`)
+	expect(t, g.Empty())
+	g = expectJSON(t, newWKBBuilder(false).header(3).uint32(0).String(),
+		`{"type":"Polygon","coordinates":[]}`)
+	expect(t, g.Empty())
+	g = expectJSON(t, newWKBBuilder(false).header(7).uint32(0).String(),
+		`{"type":"GeometryCollection","geometries":[]}`)
+	expect(t, g.Empty())
+	b := newWKBBuilder(false).header(4).uint32(2)
+	b.header(1).floats(math.NaN(), math.NaN())
+	b.header(1).floats(3, 4)
+	expectJSON(t, b.String(), `{"type":"MultiPoint","coordinates":[[3,4]]}`)
+}
+
+func TestWKBParseInvalid(t *testing.T) {
+	expectJSON(t, "\x01", errDataInvalid)
+	expectJSON(t, "\x01\x01\x00\x00", errDataInvalid)
+	expectJSON(t, newWKBBuilder(false).header(1).floats(1).String(),
+		errCoordinatesInvalid)
+	expectJSON(t, newWKBBuilder(false).header(8).String(), errTypeInvalid)
+	expectJSON(t, newWKBBuilder(false).header(4000).String(), errDataInvalid)
+	expectJSON(t, newWKBBuilder(false).header(2).uint32(1).floats(1, 2).String(),
+		errCoordinatesInvalid)
+	expectJSON(t, newWKBBuilder(false).header(2).uint32(1000).floats(1, 2).String(),
+		errCoordinatesInvalid)
+	expectJSON(t, newWKBBuilder(false).header(3).uint32(1).uint32(4).
+		floats(0, 0, 10, 0, 10, 10, 0, 1).String(), errCoordinatesInvalid)
+	expectJSON(t, newWKBBuilder(false).header(1).floats(1, 2, 3).String(),
+		errDataInvalid)
+	b := newWKBBuilder(false).header(4).uint32(1)
+	b.header(2).uint32(2).floats(1, 2, 3, 4)
+	expectJSON(t, b.String(), errTypeInvalid)
+	expectJSON(t, " "+newWKBBuilder(false).header(1).floats(1, 2).String(),
+		errDataInvalid)
+}
+
+func TestWKBParseOptions(t *testing.T) {
+	g, err := Parse(newWKBBuilder(false).header(1).floats(1, 2).String(),
+		&ParseOptions{AllowSimplePoints: true})
+	expect(t, err == nil)
+	_, ok := g.(*SimplePoint)
+	expect(t, ok)
+	g, err = Parse(newWKBBuilder(false).header(3).uint32(1).uint32(5).
+		floats(0, 0, 10, 0, 10, 10, 0, 10, 0, 0).String(),
+		&ParseOptions{AllowRects: true})
+	expect(t, err == nil)
+	_, ok = g.(*Rect)
+	expect(t, ok)
+	expectJSONOpts(t, newWKBBuilder(false).header(1).floats(1, 200).String(),
+		errCoordinatesInvalid, &ParseOptions{RequireValid: true})
+	b := newWKBBuilder(false).header(7).uint32(1)
+	b.header(1).floats(1, 200)
+	expectJSONOpts(t, b.String(), errCoordinatesInvalid,
+		&ParseOptions{RequireValid: true})
+}
+
+func TestWKBAppend(t *testing.T) {
+	for _, wkt := range []string{
+		`POINT(1 2)`,
+		`POINT Z (1 2 3)`,
+		`POINT M (1 2 3)`,
+		`POINT ZM (1 2 3 4)`,
+		`POINT EMPTY`,
+		`LINESTRING(1 2,3 4.5)`,
+		`LINESTRING EMPTY`,
+		`POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,8 2,8 8,2 8,2 2))`,
+		`POLYGON ZM ((0 0 1 2,10 0 3 4,10 10 5 6,0 0 1 2))`,
+		`POLYGON EMPTY`,
+		`MULTIPOINT Z (1 2 3,3 4 5)`,
+		`MULTILINESTRING((1 2,3 4),(5 6,7 8))`,
+		`MULTIPOLYGON(((0 0,10 0,10 10,0 0)),((20 20,30 20,30 30,20 20)))`,
+		`GEOMETRYCOLLECTION(POINT(1 2),LINESTRING Z (1 2 3,3 4 5))`,
+		`GEOMETRYCOLLECTION EMPTY`,
+	} {
+		g, err := Parse(wkt, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", wkt, err)
+		}
+		for _, opts := range []*WKBOptions{
+			nil, {BigEndian: true}, {SRID: 4326}, {BigEndian: true, SRID: 3857},
+		} {
+			g2, err := Parse(string(g.AppendWKB(nil, opts)), nil)
+			if err != nil {
+				t.Fatalf("%s: %v", wkt, err)
+			}
+			if g2.WKT() != wkt {
+				t.Fatalf("expected '%s', got '%s'", wkt, g2.WKT())
+			}
+		}
+	}
+}
+
+func TestWKBAppendBytes(t *testing.T) {
+	g := PO(1, 2)
+	expect(t, string(g.AppendWKB(nil, nil)) ==
+		newWKBBuilder(false).header(1).floats(1, 2).String())
+	expect(t, string(g.AppendWKB(nil, &WKBOptions{BigEndian: true})) ==
+		newWKBBuilder(true).header(1).floats(1, 2).String())
+	g = NewPointZ(P(1, 2), 3)
+	expect(t, string(g.AppendWKB(nil, nil)) ==
+		newWKBBuilder(false).header(1001).floats(1, 2, 3).String())
+	expect(t, string(g.AppendWKB(nil, &WKBOptions{SRID: 4326})) ==
+		newWKBBuilder(false).header(1|ewkbZ|ewkbSRID).uint32(4326).
+			floats(1, 2, 3).String())
+	// only the outer geometry has an SRID
+	mp := MPO([]geometry.Point{P(1, 2)})
+	b := newWKBBuilder(false).header(4 | ewkbSRID).uint32(4326).uint32(1)
+	b.header(1).floats(1, 2)
+	expect(t, string(mp.AppendWKB(nil, &WKBOptions{SRID: 4326})) == b.String())
+	expect(t, string(RO(0, 0, 1, 1).AppendWKB(nil, nil)) ==
+		newWKBBuilder(false).header(3).uint32(1).uint32(5).
+			floats(0, 0, 1, 0, 1, 1, 0, 1, 0, 0).String())
+}
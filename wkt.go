@@ -0,0 +1,665 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// wktReader reads well-known text from a string.
+type wktReader struct {
+	data string
+	pos  int
+	opts *ParseOptions
+}
+
+// wktDims tracks the coordinate dimensions of a single geometry.
+type wktDims struct {
+	tag   string // "", "Z", "M", or "ZM"
+	count int    // number of values per coordinate, zero until known
+	ex    *extra
+}
+
+// parseWKT parses well-known text, such as "POINT(10 20)". An extended
+// well-known text "SRID=4326;" prefix is allowed and ignored.
+func parseWKT(data string, opts *ParseOptions) (Object, error) {
+	rd := &wktReader{data: data, opts: opts}
+	rd.skipSpace()
+	if strings.HasPrefix(strings.ToUpper(rd.data[rd.pos:]), "SRID=") {
+		i := strings.IndexByte(rd.data[rd.pos:], ';')
+		if i == -1 {
+			return nil, errDataInvalid
+		}
+		rd.pos += i + 1
+	}
+	o, err := rd.readGeometry()
+	if err != nil {
+		return nil, err
+	}
+	rd.skipSpace()
+	if rd.pos < len(rd.data) {
+		return nil, errDataInvalid
+	}
+	return o, nil
+}
+
+func (rd *wktReader) skipSpace() {
+	for rd.pos < len(rd.data) {
+		switch rd.data[rd.pos] {
+		case ' ', '\t', '\n', '\r':
+			rd.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-whitespace byte, or zero at the end of the data.
+func (rd *wktReader) peek() byte {
+	rd.skipSpace()
+	if rd.pos < len(rd.data) {
+		return rd.data[rd.pos]
+	}
+	return 0
+}
+
+// expect consumes the next non-whitespace byte if it matches c.
+func (rd *wktReader) expect(c byte) bool {
+	if rd.peek() != c {
+		return false
+	}
+	rd.pos++
+	return true
+}
+
+// peekWord returns the next upper-cased keyword and the position that follows
+// it, without consuming it.
+func (rd *wktReader) peekWord() (string, int) {
+	rd.skipSpace()
+	i := rd.pos
+	for ; i < len(rd.data); i++ {
+		c := rd.data[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			break
+		}
+	}
+	return strings.ToUpper(rd.data[rd.pos:i]), i
+}
+
+func (rd *wktReader) readWord() string {
+	word, end := rd.peekWord()
+	rd.pos = end
+	return word
+}
+
+// readEmpty consumes the EMPTY keyword, if it's next.
+func (rd *wktReader) readEmpty() bool {
+	word, end := rd.peekWord()
+	if word == "EMPTY" {
+		rd.pos = end
+		return true
+	}
+	return false
+}
+
+func (rd *wktReader) readNumber() (float64, bool) {
+	rd.skipSpace()
+	i := rd.pos
+	for ; i < len(rd.data); i++ {
+		switch rd.data[i] {
+		case ' ', '\t', '\n', '\r', ',', '(', ')':
+		default:
+			continue
+		}
+		break
+	}
+	f, err := strconv.ParseFloat(rd.data[rd.pos:i], 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		// ParseFloat accepts "nan" and "inf", which are not numbers in WKT
+		return 0, false
+	}
+	rd.pos = i
+	return f, true
+}
+
+// wktSplitType splits a geometry keyword such as "POINTZ" into its type and
+// dimension tag.
+func wktSplitType(word string) (typ, tag string) {
+	for _, tag := range []string{"ZM", "Z", "M"} {
+		typ := strings.TrimSuffix(word, tag)
+		switch typ {
+		case "POINT", "LINESTRING", "POLYGON", "MULTIPOINT",
+			"MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+			if typ != word {
+				return typ, tag
+			}
+		}
+	}
+	return word, ""
+}
+
+func (rd *wktReader) readGeometry() (Object, error) {
+	word := rd.readWord()
+	if word == "" {
+		return nil, errDataInvalid
+	}
+	typ, tag := wktSplitType(word)
+	if tag == "" {
+		switch next, end := rd.peekWord(); next {
+		case "Z", "M", "ZM":
+			tag = next
+			rd.pos = end
+		}
+	}
+	empty := rd.readEmpty()
+	if !empty && rd.peek() != '(' {
+		return nil, errDataInvalid
+	}
+	switch typ {
+	default:
+		return nil, fmt.Errorf(fmtErrTypeIsUnknown, typ)
+	case "POINT":
+		return rd.readPointText(tag, empty)
+	case "LINESTRING":
+		return rd.readLineStringText(tag, empty)
+	case "POLYGON":
+		return rd.readPolygonText(tag, empty)
+	case "MULTIPOINT":
+		return rd.readMultiPointText(tag, empty)
+	case "MULTILINESTRING":
+		return rd.readMultiLineStringText(tag, empty)
+	case "MULTIPOLYGON":
+		return rd.readMultiPolygonText(tag, empty)
+	case "GEOMETRYCOLLECTION":
+		return rd.readGeometryCollectionText(empty)
+	}
+}
+
+// readPoint reads a single coordinate, such as "10 20 30", and appends any
+// Z/M values to the dims extra.
+func (rd *wktReader) readPoint(dims *wktDims) (geometry.Point, error) {
+	var nums [4]float64
+	var count int
+	for {
+		c := rd.peek()
+		if c == ',' || c == ')' || c == 0 {
+			break
+		}
+		if count == len(nums) {
+			return geometry.Point{}, errCoordinatesInvalid
+		}
+		f, ok := rd.readNumber()
+		if !ok {
+			return geometry.Point{}, errCoordinatesInvalid
+		}
+		nums[count] = f
+		count++
+	}
+	if count < 2 {
+		return geometry.Point{}, errCoordinatesInvalid
+	}
+	switch dims.tag {
+	case "Z", "M":
+		if count != 3 {
+			return geometry.Point{}, errCoordinatesInvalid
+		}
+	case "ZM":
+		if count != 4 {
+			return geometry.Point{}, errCoordinatesInvalid
+		}
+	}
+	if dims.count == 0 {
+		dims.count = count
+		if count > 2 {
+			dims.ex = new(extra)
+			dims.ex.dims = byte(count - 2)
+			dims.ex.measure = dims.tag == "M"
+		}
+	} else if count != dims.count {
+		return geometry.Point{}, errCoordinatesInvalid
+	}
+	if dims.ex != nil {
+		dims.ex.values = append(dims.ex.values, nums[2:count]...)
+	}
+	return geometry.Point{X: nums[0], Y: nums[1]}, nil
+}
+
+// readPoints reads a parenthesized coordinate sequence, such as
+// "(10 20, 30 40)".
+func (rd *wktReader) readPoints(dims *wktDims) ([]geometry.Point, error) {
+	if !rd.expect('(') {
+		return nil, errCoordinatesInvalid
+	}
+	var points []geometry.Point
+	for {
+		point, err := rd.readPoint(dims)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+		if !rd.expect(',') {
+			break
+		}
+	}
+	if !rd.expect(')') {
+		return nil, errCoordinatesInvalid
+	}
+	return points, nil
+}
+
+// readRings reads the parenthesized rings of a polygon, such as
+// "((0 0, 10 0, 10 10, 0 0))".
+func (rd *wktReader) readRings(dims *wktDims) ([][]geometry.Point, error) {
+	if !rd.expect('(') {
+		return nil, errCoordinatesInvalid
+	}
+	var rings [][]geometry.Point
+	for {
+		ring, err := rd.readPoints(dims)
+		if err != nil {
+			return nil, err
+		}
+		if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+			return nil, errCoordinatesInvalid // must be a linear ring
+		}
+		rings = append(rings, ring)
+		if !rd.expect(',') {
+			break
+		}
+	}
+	if !rd.expect(')') {
+		return nil, errCoordinatesInvalid
+	}
+	return rings, nil
+}
+
+func (rd *wktReader) readPointText(tag string, empty bool) (Object, error) {
+	if empty {
+		return newEmptyPoint(nil), nil
+	}
+	var o Object
+	dims := wktDims{tag: tag}
+	rd.expect('(')
+	point, err := rd.readPoint(&dims)
+	if err != nil {
+		return nil, err
+	}
+	if !rd.expect(')') {
+		return nil, errCoordinatesInvalid
+	}
+	if dims.ex == nil && rd.opts.AllowSimplePoints {
+		o = &SimplePoint{Point: point}
+	} else {
+		o = &Point{base: point, extra: dims.ex}
+	}
+	if rd.opts.RequireValid {
+		if !o.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	return o, nil
+}
+
+func (rd *wktReader) readLineStringText(tag string, empty bool) (
+	Object, error,
+) {
+	dims := wktDims{tag: tag}
+	var points []geometry.Point
+	if !empty {
+		var err error
+		points, err = rd.readPoints(&dims)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) < 2 {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	gopts := toGeometryOpts(rd.opts)
+	g := &LineString{base: *geometry.NewLine(points, &gopts), extra: dims.ex}
+	if rd.opts.RequireValid {
+		if !g.Valid() {
+			return nil, errDataInvalid
+		}
+	}
+	return g, nil
+}
+
+func (rd *wktReader) readPolygonText(tag string, empty bool) (Object, error) {
+	if empty {
+		gopts := toGeometryOpts(rd.opts)
+		return &Polygon{base: *geometry.NewPoly(nil, nil, &gopts)}, nil
+	}
+	dims := wktDims{tag: tag}
+	rings, err := rd.readRings(&dims)
+	if err != nil {
+		return nil, err
+	}
+	o := parsePolygonObject(rings, dims.ex, rd.opts)
+	if rd.opts.RequireValid {
+		if !o.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	return o, nil
+}
+
+func (rd *wktReader) readMultiPointText(tag string, empty bool) (
+	Object, error,
+) {
+	var g MultiPoint
+	if !empty {
+		rd.expect('(')
+	}
+	for !empty {
+		if !rd.readEmpty() {
+			dims := wktDims{tag: tag}
+			// the parentheses around each point are optional
+			paren := rd.expect('(')
+			point, err := rd.readPoint(&dims)
+			if err != nil {
+				return nil, err
+			}
+			if paren && !rd.expect(')') {
+				return nil, errCoordinatesInvalid
+			}
+			g.children = append(g.children, &Point{base: point, extra: dims.ex})
+		}
+		if !rd.expect(',') {
+			break
+		}
+	}
+	if !empty && !rd.expect(')') {
+		return nil, errDataInvalid
+	}
+	if rd.opts.RequireValid {
+		for _, child := range g.children {
+			if !child.Valid() {
+				return nil, errCoordinatesInvalid
+			}
+		}
+	}
+	g.parseInitRectIndex(rd.opts)
+	return &g, nil
+}
+
+func (rd *wktReader) readMultiLineStringText(tag string, empty bool) (
+	Object, error,
+) {
+	var g MultiLineString
+	gopts := toGeometryOpts(rd.opts)
+	if !empty {
+		rd.expect('(')
+	}
+	for !empty {
+		if !rd.readEmpty() {
+			dims := wktDims{tag: tag}
+			points, err := rd.readPoints(&dims)
+			if err != nil {
+				return nil, err
+			}
+			if len(points) < 2 {
+				return nil, errCoordinatesInvalid
+			}
+			line := geometry.NewLine(points, &gopts)
+			g.children = append(g.children,
+				&LineString{base: *line, extra: dims.ex})
+		}
+		if !rd.expect(',') {
+			break
+		}
+	}
+	if !empty && !rd.expect(')') {
+		return nil, errDataInvalid
+	}
+	if rd.opts.RequireValid {
+		if !g.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	g.parseInitRectIndex(rd.opts)
+	return &g, nil
+}
+
+func (rd *wktReader) readMultiPolygonText(tag string, empty bool) (
+	Object, error,
+) {
+	var g MultiPolygon
+	gopts := toGeometryOpts(rd.opts)
+	if !empty {
+		rd.expect('(')
+	}
+	for !empty {
+		if !rd.readEmpty() {
+			dims := wktDims{tag: tag}
+			rings, err := rd.readRings(&dims)
+			if err != nil {
+				return nil, err
+			}
+			var holes [][]geometry.Point
+			if len(rings) > 1 {
+				holes = rings[1:]
+			}
+			poly := geometry.NewPoly(rings[0], holes, &gopts)
+			g.children = append(g.children,
+				&Polygon{base: *poly, extra: dims.ex})
+		}
+		if !rd.expect(',') {
+			break
+		}
+	}
+	if !empty && !rd.expect(')') {
+		return nil, errDataInvalid
+	}
+	if rd.opts.RequireValid {
+		if !g.Valid() {
+			return nil, errCoordinatesInvalid
+		}
+	}
+	g.parseInitRectIndex(rd.opts)
+	return &g, nil
+}
+
+func (rd *wktReader) readGeometryCollectionText(empty bool) (Object, error) {
+	var g GeometryCollection
+	if !empty {
+		rd.expect('(')
+	}
+	for !empty {
+		child, err := rd.readGeometry()
+		if err != nil {
+			return nil, err
+		}
+		g.children = append(g.children, child)
+		if !rd.expect(',') {
+			break
+		}
+	}
+	if !empty && !rd.expect(')') {
+		return nil, errDataInvalid
+	}
+	g.parseInitRectIndex(rd.opts)
+	return &g, nil
+}
+
+// extraDims returns the number of extra coordinate values and whether a
+// single extra value is an M.
+func extraDims(ex *extra) (dims int, measure bool) {
+	if ex == nil {
+		return 0, false
+	}
+	return int(ex.dims), ex.measure
+}
+
+// appendWKTTag appends the dimension tag, such as " Z", for a geometry that
+// has dims extra coordinate values.
+func appendWKTTag(dst []byte, dims int, measure bool) []byte {
+	switch {
+	case dims == 2:
+		dst = append(dst, " ZM"...)
+	case dims == 1 && measure:
+		dst = append(dst, " M"...)
+	case dims == 1:
+		dst = append(dst, " Z"...)
+	}
+	return dst
+}
+
+// appendWKTBegin appends the geometry keyword and tag, followed by the space
+// that separates a tag from its coordinates.
+func appendWKTBegin(dst []byte, keyword string, dims int, measure bool) []byte {
+	dst = append(dst, keyword...)
+	dst = appendWKTTag(dst, dims, measure)
+	if dims > 0 {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+// appendWKTPoint appends a single coordinate with dims extra values. The
+// values are read from ex at point index idx, or zero when not available.
+func appendWKTPoint(
+	dst []byte, point geometry.Point, ex *extra, idx, dims int,
+) []byte {
+	dst = strconv.AppendFloat(dst, point.X, 'f', -1, 64)
+	dst = append(dst, ' ')
+	dst = strconv.AppendFloat(dst, point.Y, 'f', -1, 64)
+	for i := 0; i < dims; i++ {
+		var v float64
+		if ex != nil && i < int(ex.dims) {
+			v = ex.values[idx*int(ex.dims)+i]
+		}
+		dst = append(dst, ' ')
+		dst = strconv.AppendFloat(dst, v, 'f', -1, 64)
+	}
+	return dst
+}
+
+func appendWKTSeries(
+	dst []byte, series geometry.Series, ex *extra, pidx, dims int,
+) (ndst []byte, npidx int) {
+	dst = append(dst, '(')
+	nPoints := series.NumPoints()
+	for i := 0; i < nPoints; i++ {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendWKTPoint(dst, series.PointAt(i), ex, pidx, dims)
+		pidx++
+	}
+	dst = append(dst, ')')
+	return dst, pidx
+}
+
+// appendWKTObject appends a Point, LineString, Polygon, or Rect as
+// well-known text.
+func appendWKTObject(dst []byte, keyword string, obj Object, ex *extra) []byte {
+	dims, measure := extraDims(ex)
+	dst = appendWKTBegin(dst, keyword, dims, measure)
+	if obj.Empty() || math.IsNaN(obj.Center().X) {
+		if dims == 0 {
+			dst = append(dst, ' ')
+		}
+		return append(dst, "EMPTY"...)
+	}
+	if keyword == "POINT" {
+		dst = append(dst, '(')
+		dst = appendWKTCoords(dst, obj, dims)
+		return append(dst, ')')
+	}
+	return appendWKTCoords(dst, obj, dims)
+}
+
+// appendWKTCoords appends the coordinates of a Point, LineString, or Polygon
+// without its keyword, such as "1 2" or "((0 0,1 0,1 1,0 0))".
+func appendWKTCoords(dst []byte, obj Object, dims int) []byte {
+	switch obj := obj.(type) {
+	case *Point:
+		return appendWKTPoint(dst, obj.base, obj.extra, 0, dims)
+	case *SimplePoint:
+		return appendWKTPoint(dst, obj.Point, nil, 0, dims)
+	case *LineString:
+		if obj.Empty() {
+			return append(dst, "EMPTY"...)
+		}
+		dst, _ = appendWKTSeries(dst, &obj.base, obj.extra, 0, dims)
+		return dst
+	case *Polygon:
+		if obj.Empty() {
+			return append(dst, "EMPTY"...)
+		}
+		var pidx int
+		dst = append(dst, '(')
+		dst, pidx = appendWKTSeries(dst, obj.base.Exterior, obj.extra, pidx,
+			dims)
+		for _, hole := range obj.base.Holes {
+			dst = append(dst, ',')
+			dst, pidx = appendWKTSeries(dst, hole, obj.extra, pidx, dims)
+		}
+		return append(dst, ')')
+	case *Rect:
+		return appendWKTCoords(dst, obj.Polygon(), dims)
+	}
+	return append(dst, "EMPTY"...)
+}
+
+// childrenDims returns the largest coordinate dimensions of the Point,
+// LineString, and Polygon children.
+func childrenDims(children []Object) (dims int, measure bool) {
+	for _, child := range children {
+		var cdims int
+		var cmeasure bool
+		switch child := child.(type) {
+		case *Point:
+			cdims, cmeasure = extraDims(child.extra)
+		case *LineString:
+			cdims, cmeasure = extraDims(child.extra)
+		case *Polygon:
+			cdims, cmeasure = extraDims(child.extra)
+		}
+		if cdims > dims {
+			dims, measure = cdims, cmeasure
+		}
+	}
+	return dims, measure
+}
+
+// appendWKTChildren appends a Multi* geometry from its children using the
+// largest coordinate dimensions of all children.
+func appendWKTChildren(dst []byte, keyword string, children []Object) []byte {
+	dims, measure := childrenDims(children)
+	dst = appendWKTBegin(dst, keyword, dims, measure)
+	if len(children) == 0 {
+		if dims == 0 {
+			dst = append(dst, ' ')
+		}
+		return append(dst, "EMPTY"...)
+	}
+	dst = append(dst, '(')
+	for i, child := range children {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendWKTCoords(dst, child, dims)
+	}
+	return append(dst, ')')
+}
+
+// appendWKTGeometries appends a GEOMETRYCOLLECTION of objects.
+func appendWKTGeometries(dst []byte, objs []Object) []byte {
+	dst = append(dst, "GEOMETRYCOLLECTION"...)
+	if len(objs) == 0 {
+		return append(dst, " EMPTY"...)
+	}
+	dst = append(dst, '(')
+	for i, obj := range objs {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = obj.AppendWKT(dst)
+	}
+	return append(dst, ')')
+}
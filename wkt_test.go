@@ -0,0 +1,169 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestWKTParse(t *testing.T) {
+	expectJSON(t, `POINT(1 2)`, `{"type":"Point","coordinates":[1,2]}`)
+	expectJSON(t, `point (1.5 -2e1)`, `{"type":"Point","coordinates":[1.5,-20]}`)
+	expectJSON(t, `POINT Z (1 2 3)`, `{"type":"Point","coordinates":[1,2,3]}`)
+	expectJSON(t, `POINTZ(1 2 3)`, `{"type":"Point","coordinates":[1,2,3]}`)
+	expectJSON(t, `POINT ZM (1 2 3 4)`, `{"type":"Point","coordinates":[1,2,3,4]}`)
+	expectJSON(t, `POINT (1 2 3 4)`, `{"type":"Point","coordinates":[1,2,3,4]}`)
+	expectJSON(t, `SRID=4326;POINT(1 2)`, `{"type":"Point","coordinates":[1,2]}`)
+	expectJSON(t, `LINESTRING(1 2,3 4,5 6)`,
+		`{"type":"LineString","coordinates":[[1,2],[3,4],[5,6]]}`)
+	expectJSON(t, `LINESTRING Z (1 2 3,4 5 6)`,
+		`{"type":"LineString","coordinates":[[1,2,3],[4,5,6]]}`)
+	expectJSON(t, `POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,8 2,8 8,2 8,2 2))`,
+		`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[8,2],[8,8],[2,8],[2,2]]]}`)
+	expectJSON(t, `MULTIPOINT((1 2),(3 4))`,
+		`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`)
+	expectJSON(t, `MULTIPOINT(1 2,3 4)`,
+		`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`)
+	expectJSON(t, `MULTILINESTRING((1 2,3 4),(5 6,7 8))`,
+		`{"type":"MultiLineString","coordinates":[[[1,2],[3,4]],[[5,6],[7,8]]]}`)
+	expectJSON(t, `MULTIPOLYGON(((0 0,10 0,10 10,0 0)),((20 20,30 20,30 30,20 20)))`,
+		`{"type":"MultiPolygon","coordinates":[[[[0,0],[10,0],[10,10],[0,0]]],[[[20,20],[30,20],[30,30],[20,20]]]]}`)
+	expectJSON(t, `GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(1 2,3 4))`,
+		`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"LineString","coordinates":[[1,2],[3,4]]}]}`)
+	expectJSON(t, "\n GEOMETRYCOLLECTION (\n\tPOINT (1 2)\n)\n",
+		`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}]}`)
+}
+
+func TestWKTParseEmpty(t *testing.T) {
+	g := expectJSON(t, `LINESTRING EMPTY`, `{"type":"LineString","coordinates":[]}`)
+	expect(t, g.Empty())
+	g = expectJSON(t, `POLYGON EMPTY`, `{"type":"Polygon","coordinates":[]}`)
+	expect(t, g.Empty())
+	g = expectJSON(t, `MULTIPOLYGON EMPTY`, `{"type":"MultiPolygon","coordinates":[]}`)
+	expect(t, g.Empty())
+	g = expectJSON(t, `GEOMETRYCOLLECTION EMPTY`, `{"type":"GeometryCollection","geometries":[]}`)
+	expect(t, g.Empty())
+	expectJSON(t, `MULTIPOINT(EMPTY,(1 2))`, `{"type":"MultiPoint","coordinates":[[1,2]]}`)
+	g = expectJSON(t, `POINT EMPTY`, `{"type":"Point","coordinates":[]}`)
+	expect(t, g.Empty() && math.IsNaN(g.Center().X))
+	expect(t, g.JSON() == `{"type":"Point","coordinates":[]}`)
+	expect(t, g.WKT() == `POINT EMPTY`)
+	g = expectJSON(t, g.JSON(), `{"type":"Point","coordinates":[]}`)
+	expect(t, g.Empty() && g.WKT() == `POINT EMPTY`)
+	g, err := Parse(string(g.AppendWKB(nil, nil)), nil)
+	expect(t, err == nil && g.Empty() && g.WKT() == `POINT EMPTY`)
+	opts := *DefaultParseOptions
+	opts.AllowSimplePoints = true
+	g, err = Parse(`POINT EMPTY`, &opts)
+	expect(t, err == nil && g.Empty() &&
+		g.JSON() == `{"type":"Point","coordinates":[]}`)
+}
+
+func TestWKTParseMeasure(t *testing.T) {
+	g := expectJSON(t, `POINT M (1 2 3)`, `{"type":"Point","coordinates":[1,2,3]}`)
+	expect(t, g.(*Point).extra.measure)
+	g = expectJSON(t, `POINT Z (1 2 3)`, `{"type":"Point","coordinates":[1,2,3]}`)
+	expect(t, !g.(*Point).extra.measure)
+}
+
+func TestWKTParseInvalid(t *testing.T) {
+	expectJSON(t, `POINT`, errDataInvalid)
+	expectJSON(t, `POINT(1)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT(1 2 3 4 5)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT(1 a)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT(inf 2)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT(1 -Infinity)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT(nan 2)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT Z (1 2 NaN)`, errCoordinatesInvalid)
+	expectJSON(t, `LINESTRING(1 2,+inf 4)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT Z (1 2)`, errCoordinatesInvalid)
+	expectJSON(t, `POINT(1 2) POINT(1 2)`, errDataInvalid)
+	expectJSON(t, `POINT(1 2`, errCoordinatesInvalid)
+	expectJSON(t, `LINESTRING(1 2)`, errCoordinatesInvalid)
+	expectJSON(t, `LINESTRING(1 2,3 4 5)`, errCoordinatesInvalid)
+	expectJSON(t, `POLYGON((0 0,10 0,10 10,0 10))`, errCoordinatesInvalid)
+	expectJSON(t, `GEOMETRYCOLLECTION(POINT(1 2)`, errDataInvalid)
+	_, err := Parse(`TRIANGLE((0 0,1 0,0 1,0 0))`, nil)
+	expect(t, err != nil && err.Error() == "type 'TRIANGLE' is unknown")
+}
+
+func TestWKTParseOptions(t *testing.T) {
+	opts := &ParseOptions{AllowSimplePoints: true, AllowRects: true}
+	g, err := Parse(`POINT(1 2)`, opts)
+	expect(t, err == nil)
+	_, ok := g.(*SimplePoint)
+	expect(t, ok)
+	g, err = Parse(`POINT Z (1 2 3)`, opts)
+	expect(t, err == nil)
+	_, ok = g.(*Point)
+	expect(t, ok)
+	g, err = Parse(`POLYGON((0 0,10 0,10 10,0 10,0 0))`, opts)
+	expect(t, err == nil)
+	_, ok = g.(*Rect)
+	expect(t, ok)
+
+	expectJSONOpts(t, `POINT(190 1)`, errCoordinatesInvalid,
+		&ParseOptions{RequireValid: true})
+	expectJSONOpts(t, `MULTIPOLYGON(((0 0,190 0,10 10,0 0)))`,
+		errCoordinatesInvalid, &ParseOptions{RequireValid: true})
+	expectJSONOpts(t, `GEOMETRYCOLLECTION(POINT(1 200))`,
+		errCoordinatesInvalid, &ParseOptions{RequireValid: true})
+
+	g, err = Parse(`LINESTRING(0 0,1 1,2 2,3 3)`,
+		&ParseOptions{IndexGeometry: 2, IndexGeometryKind: geometry.QuadTree})
+	expect(t, err == nil)
+	expect(t, g.(*LineString).base.Index() != nil)
+}
+
+func TestWKTAppend(t *testing.T) {
+	for _, wkt := range []string{
+		`POINT(1 2)`,
+		`POINT Z (1 2 3)`,
+		`POINT M (1 2 3)`,
+		`POINT ZM (1 2 3 4)`,
+		`POINT EMPTY`,
+		`LINESTRING(1 2,3 4.5)`,
+		`LINESTRING Z (1 2 3,4 5 6)`,
+		`LINESTRING EMPTY`,
+		`POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,8 2,8 8,2 8,2 2))`,
+		`POLYGON ZM ((0 0 1 2,10 0 3 4,10 10 5 6,0 0 1 2))`,
+		`POLYGON EMPTY`,
+		`MULTIPOINT(1 2,3 4)`,
+		`MULTIPOINT Z (1 2 3,3 4 5)`,
+		`MULTIPOINT EMPTY`,
+		`MULTILINESTRING((1 2,3 4),(5 6,7 8))`,
+		`MULTIPOLYGON(((0 0,10 0,10 10,0 0)),((20 20,30 20,30 30,20 20)))`,
+		`GEOMETRYCOLLECTION(POINT(1 2),LINESTRING Z (1 2 3,3 4 5))`,
+		`GEOMETRYCOLLECTION EMPTY`,
+	} {
+		g, err := Parse(wkt, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", wkt, err)
+		}
+		if g.WKT() != wkt {
+			t.Fatalf("expected '%s', got '%s'", wkt, g.WKT())
+		}
+	}
+}
+
+func TestWKTAppendVarious(t *testing.T) {
+	expect(t, NewSimplePoint(P(1, 2)).WKT() == `POINT(1 2)`)
+	expect(t, RO(10, 20, 30, 40).WKT() ==
+		`POLYGON((10 20,30 20,30 40,10 40,10 20))`)
+	g := expectJSON(t, `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2,3]},"properties":{}}`, nil)
+	expect(t, g.WKT() == `POINT Z (1 2 3)`)
+	g = expectJSON(t, `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":{}}
+	]}`, nil)
+	expect(t, g.WKT() == `GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(1 2,3 4))`)
+	// mixed dimensions use the largest
+	g = expectJSON(t, `{"type":"MultiPoint","coordinates":[[1,2],[3,4,5]]}`, nil)
+	expect(t, g.WKT() == `MULTIPOINT Z (1 2 0,3 4 5)`)
+	// circles are encoded as their polygon
+	c := NewCircle(P(-112, 33), 1000, 16)
+	g, err := Parse(c.WKT(), nil)
+	expect(t, err == nil)
+	expect(t, g.NumPoints() == c.Polygon().NumPoints())
+}